@@ -0,0 +1,30 @@
+package about
+
+// Badge is one pill of the "Stack" row.
+type Badge struct {
+	Label string `json:"label" yaml:"label"`
+	BG    string `json:"bg" yaml:"bg"`
+	FG    string `json:"fg" yaml:"fg"`
+}
+
+// Section is a titled block of text; Body may span several lines.
+type Section struct {
+	Title string `json:"title" yaml:"title"`
+	Body  string `json:"body" yaml:"body"`
+}
+
+type Link struct {
+	Icon  string `json:"icon" yaml:"icon"`
+	Label string `json:"label" yaml:"label"`
+	URL   string `json:"url" yaml:"url"`
+}
+
+// About is the "About & Welcome" page. It is loaded from about.md in the
+// content directory: YAML front matter for the greeting, stack and links,
+// and one "## Title" Markdown heading per section.
+type About struct {
+	Greeting string    `json:"greeting" yaml:"greeting"`
+	Sections []Section `json:"sections" yaml:"sections"`
+	Stack    []Badge   `json:"stack" yaml:"stack"`
+	Links    []Link    `json:"links" yaml:"links"`
+}
@@ -0,0 +1,313 @@
+// Package blocks is a falling-blocks game. Its rules are in Game, which
+// like package snake's is pure and deterministic, and Model plays it in a
+// terminal.
+package blocks
+
+import "time"
+
+// The well is W cells across and H deep.
+const (
+	W = 10
+	H = 20
+)
+
+// Kind is a piece's shape; the zero Kind is an empty cell.
+type Kind int
+
+const (
+	None Kind = iota
+	I
+	O
+	T
+	S
+	Z
+	J
+	L
+)
+
+var kinds = []Kind{I, O, T, S, Z, J, L}
+
+// shapes are the cells of each kind unrotated, in a box of side size.
+var shapes = map[Kind]struct {
+	size  int
+	cells [4]Point
+}{
+	I: {4, [4]Point{{0, 1}, {1, 1}, {2, 1}, {3, 1}}},
+	O: {2, [4]Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}}},
+	T: {3, [4]Point{{1, 0}, {0, 1}, {1, 1}, {2, 1}}},
+	S: {3, [4]Point{{1, 0}, {2, 0}, {0, 1}, {1, 1}}},
+	Z: {3, [4]Point{{0, 0}, {1, 0}, {1, 1}, {2, 1}}},
+	J: {3, [4]Point{{0, 0}, {0, 1}, {1, 1}, {2, 1}}},
+	L: {3, [4]Point{{2, 0}, {0, 1}, {1, 1}, {2, 1}}},
+}
+
+// kicks are the nudges tried, in order, when a piece can't turn where it
+// is: against a wall or the stack it shifts over, or up.
+var kicks = []Point{{0, 0}, {-1, 0}, {1, 0}, {0, -1}, {-1, -1}, {1, -1}, {-2, 0}, {2, 0}}
+
+// lineScores is what clearing 1 to 4 lines at once scores, times the level.
+var lineScores = [...]int{0, 100, 300, 500, 800}
+
+// linesPerLevel is how many lines take the game up a level.
+const linesPerLevel = 10
+
+// Gravity pulls a piece down a row every dropInterval at level 1, and a
+// fifth faster each level after, down to minInterval.
+const (
+	dropInterval = 800 * time.Millisecond
+	minInterval  = 60 * time.Millisecond
+)
+
+// Point is a cell of the well, from the top-left corner.
+type Point struct{ X, Y int }
+
+// Piece is a falling piece: its kind, turned Rot quarter turns clockwise,
+// with its box's corner at X, Y. Rows above the well have negative Y.
+type Piece struct {
+	Kind Kind
+	Rot  int
+	X, Y int
+}
+
+// Cells are the cells p covers.
+func (p Piece) Cells() [4]Point {
+	s := shapes[p.Kind]
+	out := s.cells
+	for i, c := range out {
+		for range p.Rot {
+			c = Point{s.size - 1 - c.Y, c.X}
+		}
+		out[i] = Point{p.X + c.X, p.Y + c.Y}
+	}
+	return out
+}
+
+// Rand is where the order of pieces comes from; *rand.Rand will do.
+type Rand interface {
+	Intn(n int) int
+}
+
+// State is everything needed to draw a game.
+type State struct {
+	// Well holds the pieces that have landed, by row then column.
+	Well [H][W]Kind
+	// Piece is the one falling; Next comes after it, and Held is put aside
+	// to swap back in later.
+	Piece Piece
+	Next  Kind
+	Held  Kind
+	// CanHold is cleared by holding and set again when a piece lands, so a
+	// piece can't be swapped back and forth.
+	CanHold bool
+	Score   int
+	Lines   int
+	Level   int
+	Over    bool
+}
+
+// Interval is how long a piece takes to fall a row at the current level.
+func (st State) Interval() time.Duration {
+	d := dropInterval
+	for range st.Level - 1 {
+		d = d * 4 / 5
+	}
+	return max(d, minInterval)
+}
+
+// Game is a game of falling blocks.
+type Game struct {
+	State
+	rng Rand
+	// bag deals each kind once, in a shuffled order, before any repeats.
+	bag []Kind
+}
+
+func NewGame(rng Rand) *Game {
+	g := &Game{State: State{Level: 1, CanHold: true}, rng: rng}
+	g.Next = g.deal()
+	g.spawn(g.deal())
+	return g
+}
+
+func (g *Game) deal() Kind {
+	if len(g.bag) == 0 {
+		g.bag = append(g.bag, kinds...)
+		for i := len(g.bag) - 1; i > 0; i-- {
+			j := g.rng.Intn(i + 1)
+			g.bag[i], g.bag[j] = g.bag[j], g.bag[i]
+		}
+	}
+	k := g.bag[0]
+	g.bag = g.bag[1:]
+	return k
+}
+
+// spawn drops k in at the top. If there's no room for it, the game is
+// over.
+func (g *Game) spawn(k Kind) {
+	size := shapes[k].size
+	g.Piece = Piece{Kind: k, X: (W - size) / 2}
+	if k == I {
+		g.Piece.Y = -1
+	}
+	if !g.fits(g.Piece) {
+		g.Over = true
+	}
+}
+
+// fits reports whether p is inside the well, or above it, and clear of the
+// stack.
+func (g *Game) fits(p Piece) bool {
+	for _, c := range p.Cells() {
+		if c.X < 0 || c.X >= W || c.Y >= H {
+			return false
+		}
+		if c.Y >= 0 && g.Well[c.Y][c.X] != None {
+			return false
+		}
+	}
+	return true
+}
+
+// Move shifts the piece dx columns, if there's room.
+func (g *Game) Move(dx int) bool {
+	p := g.Piece
+	p.X += dx
+	return g.try(p)
+}
+
+// Rotate turns the piece a quarter clockwise, or anticlockwise when cw is
+// false, kicking it clear of walls and the stack if it needs to.
+func (g *Game) Rotate(cw bool) bool {
+	if g.Over || g.Piece.Kind == O {
+		return false
+	}
+	p := g.Piece
+	if cw {
+		p.Rot = (p.Rot + 1) % 4
+	} else {
+		p.Rot = (p.Rot + 3) % 4
+	}
+	for _, k := range kicks {
+		if g.try(Piece{Kind: p.Kind, Rot: p.Rot, X: p.X + k.X, Y: p.Y + k.Y}) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) try(p Piece) bool {
+	if g.Over || !g.fits(p) {
+		return false
+	}
+	g.Piece = p
+	return true
+}
+
+// Tick lets gravity pull the piece down a row, landing it if it can't
+// fall any further. It reports how many lines landing it cleared.
+func (g *Game) Tick() int {
+	if g.Over {
+		return 0
+	}
+	p := g.Piece
+	p.Y++
+	if g.try(p) {
+		return 0
+	}
+	return g.land()
+}
+
+// SoftDrop pushes the piece down a row for a point.
+func (g *Game) SoftDrop() int {
+	if g.Over {
+		return 0
+	}
+	p := g.Piece
+	p.Y++
+	if g.try(p) {
+		g.Score++
+		return 0
+	}
+	return g.land()
+}
+
+// HardDrop drops the piece straight down, for two points a row, and lands
+// it.
+func (g *Game) HardDrop() int {
+	if g.Over {
+		return 0
+	}
+	ghost := g.Ghost()
+	g.Score += 2 * (ghost.Y - g.Piece.Y)
+	g.Piece = ghost
+	return g.land()
+}
+
+// Ghost is where the piece would land if dropped now.
+func (g *Game) Ghost() Piece {
+	p := g.Piece
+	for {
+		below := p
+		below.Y++
+		if !g.fits(below) {
+			return p
+		}
+		p = below
+	}
+}
+
+// Hold puts the piece aside and brings in the one held before, or the
+// next one the first time.
+func (g *Game) Hold() bool {
+	if g.Over || !g.CanHold {
+		return false
+	}
+	held := g.Held
+	g.Held = g.Piece.Kind
+	if held == None {
+		held = g.Next
+		g.Next = g.deal()
+	}
+	g.spawn(held)
+	g.CanHold = false
+	return true
+}
+
+// land fixes the piece in the well, clears any full lines and brings in
+// the next piece. A piece landing partly above the well ends the game.
+func (g *Game) land() int {
+	for _, c := range g.Piece.Cells() {
+		if c.Y < 0 {
+			g.Over = true
+			return 0
+		}
+		g.Well[c.Y][c.X] = g.Piece.Kind
+	}
+
+	cleared := 0
+	for y := H - 1; y >= 0; y-- {
+		full := true
+		for _, k := range g.Well[y] {
+			if k == None {
+				full = false
+				break
+			}
+		}
+		if !full {
+			continue
+		}
+		cleared++
+		copy(g.Well[1:y+1], g.Well[:y])
+		g.Well[0] = [W]Kind{}
+		y++
+	}
+	g.Score += lineScores[cleared] * g.Level
+	g.Lines += cleared
+	g.Level = g.Lines/linesPerLevel + 1
+
+	g.CanHold = true
+	g.spawn(g.Next)
+	g.Next = g.deal()
+	return cleared
+}
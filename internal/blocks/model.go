@@ -0,0 +1,387 @@
+package blocks
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/koossaayy/ssh-portal/internal/game"
+	"github.com/koossaayy/ssh-portal/internal/scores"
+)
+
+// Name is the game's key in the score store.
+const Name = "blocks"
+
+// Info puts Blocks on the Games menu.
+var Info = game.Info{
+	Name: "Blocks",
+	Icon: "🧱",
+	Desc: "Turn and drop falling blocks to clear lines",
+	New: func(s game.Session) game.Game {
+		return New(s.Renderer, s.Width, s.Height, s.Player)
+	},
+	Boards: func(*scores.Store, time.Time) []game.Board {
+		return []game.Board{{Name: "Blocks", Key: Name}}
+	},
+}
+
+// The well takes two columns a cell. minW and minH are the smallest
+// terminal it's drawn on in full, beside the side panel; roomyH is tall
+// enough for the airy layout.
+const (
+	minW   = 52
+	minH   = 24
+	roomyH = 27
+)
+
+// colors are the Dracula colours each kind of piece is drawn in.
+var colors = map[Kind]lipgloss.Color{
+	I: lipgloss.Color("#8BE9FD"),
+	O: lipgloss.Color("#F1FA8C"),
+	T: lipgloss.Color("#BD93F9"),
+	S: lipgloss.Color("#50FA7B"),
+	Z: lipgloss.Color("#FF5555"),
+	J: lipgloss.Color("#FF79C6"),
+	L: lipgloss.Color("#FFB86C"),
+}
+
+// tickMsg lets gravity pull the piece down; gen drops ticks scheduled
+// before a pause or a new round.
+type tickMsg struct{ gen int }
+
+func (m Model) tick() tea.Cmd {
+	gen := m.gen
+	return tea.Tick(m.engine.Interval(), func(time.Time) tea.Msg {
+		return tickMsg{gen: gen}
+	})
+}
+
+// Model plays falling blocks in a terminal: keys move and turn the piece,
+// and every tick gravity pulls it down a row.
+type Model struct {
+	renderer  *lipgloss.Renderer
+	width     int
+	height    int
+	engine    *Game
+	rng       *rand.Rand
+	paused    bool
+	gen       int
+	highScore int
+	quit      bool
+
+	// player is nil for anonymous visitors, whose scores aren't kept.
+	player  *scores.Player
+	newBest bool
+	saveErr string
+}
+
+func New(r *lipgloss.Renderer, w, h int, player *scores.Player) Model {
+	m := Model{
+		renderer:  r,
+		width:     w,
+		height:    h,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		player:    player,
+		highScore: player.Best(Name),
+	}
+	m.reset()
+	return m
+}
+
+func (m *Model) reset() {
+	m.engine = NewGame(m.rng)
+	m.paused = false
+	m.gen++
+	m.newBest = false
+	m.saveErr = ""
+}
+
+// Done reports whether the player has asked to leave the game.
+func (m Model) Done() bool {
+	return m.quit
+}
+
+func (m Model) ScoreKey() string {
+	return Name
+}
+
+// gameOver ends the round and saves the score.
+func (m *Model) gameOver() {
+	score := m.engine.Score
+	if score > m.highScore {
+		m.highScore = score
+		m.newBest = m.player != nil
+	}
+	if score == 0 {
+		return
+	}
+	if err := m.player.Record(Name, score); err != nil {
+		m.saveErr = err.Error()
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.tick()
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		if !m.fits() && !m.paused && !m.engine.Over {
+			m.paused = true
+			m.gen++
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			m.quit = true
+			return m, nil
+		case "p":
+			switch {
+			case m.engine.Over:
+			case !m.paused:
+				m.paused = true
+				m.gen++
+			case m.fits():
+				m.paused = false
+				m.gen++
+				return m, m.tick()
+			}
+			return m, nil
+		}
+		if m.engine.Over {
+			if msg.String() == "enter" {
+				m.reset()
+				return m, m.tick()
+			}
+			return m, nil
+		}
+		if m.paused {
+			return m, nil
+		}
+		switch msg.String() {
+		case "left", "h", "a":
+			m.engine.Move(-1)
+		case "right", "l", "d":
+			m.engine.Move(1)
+		case "up", "k", "w", "x":
+			m.engine.Rotate(true)
+		case "z":
+			m.engine.Rotate(false)
+		case "down", "j", "s":
+			m.engine.SoftDrop()
+		case " ":
+			m.engine.HardDrop()
+		case "c":
+			m.engine.Hold()
+		}
+		if m.engine.Over {
+			m.gameOver()
+		}
+		return m, nil
+
+	case tickMsg:
+		if msg.gen != m.gen || m.paused || m.engine.Over {
+			return m, nil
+		}
+		m.engine.Tick()
+		if m.engine.Over {
+			m.gameOver()
+			return m, nil
+		}
+		return m, m.tick()
+	}
+	return m, nil
+}
+
+// fits reports whether the well and the side panel can be drawn in full.
+func (m Model) fits() bool {
+	return m.width >= minW && m.height >= minH
+}
+
+func (m Model) View() string {
+	r := m.renderer
+
+	pink   := lipgloss.Color("#FF79C6")
+	cyan   := lipgloss.Color("#8BE9FD")
+	yellow := lipgloss.Color("#F1FA8C")
+	green  := lipgloss.Color("#50FA7B")
+	red    := lipgloss.Color("#FF5555")
+	fg     := lipgloss.Color("#F8F8F2")
+	subtle := lipgloss.Color("#6272A4")
+	purple := lipgloss.Color("#9B72CF")
+	dim    := lipgloss.Color("#44475A")
+
+	footStyle := r.NewStyle().Foreground(subtle).Italic(true)
+
+	if !m.fits() {
+		var sb strings.Builder
+		sb.WriteString("\n")
+		sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  🖥  Your terminal is too small for Blocks"))
+		sb.WriteString("\n\n")
+		sb.WriteString(r.NewStyle().Foreground(fg).Render(fmt.Sprintf("  It's %d×%d; the well needs at least %d×%d.", m.width, m.height, minW, minH)))
+		sb.WriteString("\n")
+		sb.WriteString(r.NewStyle().Foreground(fg).Render("  Make the window bigger, then p to carry on."))
+		sb.WriteString("\n\n")
+		sb.WriteString(footStyle.Render("  esc to go back"))
+		return sb.String()
+	}
+
+	falling := m.engine.Piece
+	ghost := m.engine.Ghost()
+	at := func(p Piece, x, y int) bool {
+		for _, c := range p.Cells() {
+			if c.X == x && c.Y == y {
+				return true
+			}
+		}
+		return false
+	}
+	var well strings.Builder
+	for y := range H {
+		for x := range W {
+			k := m.engine.Well[y][x]
+			switch {
+			case k != None:
+				well.WriteString(r.NewStyle().Foreground(colors[k]).Render("██"))
+			case !m.engine.Over && at(falling, x, y):
+				well.WriteString(r.NewStyle().Foreground(colors[falling.Kind]).Render("██"))
+			case !m.engine.Over && at(ghost, x, y):
+				well.WriteString(r.NewStyle().Foreground(colors[falling.Kind]).Faint(true).Render("░░"))
+			default:
+				well.WriteString(r.NewStyle().Foreground(dim).Render(" ·"))
+			}
+		}
+		if y < H-1 {
+			well.WriteString("\n")
+		}
+	}
+	wellStyle := r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(purple).
+		Padding(0, 1)
+	board := wellStyle.Render(well.String())
+
+	// preview draws a piece unturned in a box two rows deep, or a blank
+	// box for none.
+	preview := func(k Kind, faint bool) string {
+		rows := [2][4]bool{}
+		if k != None {
+			top := H
+			for _, c := range (Piece{Kind: k}).Cells() {
+				top = min(top, c.Y)
+			}
+			for _, c := range (Piece{Kind: k}).Cells() {
+				rows[c.Y-top][c.X] = true
+			}
+		}
+		style := r.NewStyle().Foreground(colors[k]).Faint(faint)
+		var lines []string
+		for _, row := range rows {
+			line := " "
+			for _, on := range row {
+				if on {
+					line += style.Render("██")
+				} else {
+					line += "  "
+				}
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	label := func(s string) string { return r.NewStyle().Foreground(subtle).Render(s) }
+	value := func(color lipgloss.Color, n int) string {
+		return r.NewStyle().Foreground(color).Bold(true).Render(fmt.Sprintf(" %d", n))
+	}
+	stats := strings.Join([]string{
+		r.NewStyle().Foreground(yellow).Bold(true).Render("🧱 BLOCKS"),
+		"",
+		label("NEXT"),
+		preview(m.engine.Next, false),
+		"",
+		label("HOLD"),
+		preview(m.engine.Held, !m.engine.CanHold),
+		"",
+		label("SCORE"),
+		value(pink, m.engine.Score),
+		"",
+		label("LEVEL"),
+		value(cyan, m.engine.Level),
+		"",
+		label("LINES"),
+		value(fg, m.engine.Lines),
+		"",
+		label("HIGH SCORE"),
+		value(yellow, m.highScore),
+	}, "\n")
+	statsPanel := r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(cyan).
+		Padding(0, 2).
+		Width(18).
+		Render(stats)
+
+	// inWell draws a box of lines in the well's place, wrapping them to
+	// fit.
+	inWell := func(color lipgloss.Color, border lipgloss.Border, lines []string) string {
+		box := r.NewStyle().
+			Border(border).
+			BorderForeground(color).
+			Padding(1, 1).
+			Width(lipgloss.Width(board) - 2).
+			Render(strings.Join(lines, "\n"))
+		return lipgloss.Place(lipgloss.Width(board), lipgloss.Height(board), lipgloss.Center, lipgloss.Center, box)
+	}
+
+	foot := "← → move  •  ↑ z turn  •  ↓ space drop  •  c hold  •  p pause  •  esc back"
+	switch {
+	case m.engine.Over:
+		lines := []string{
+			r.NewStyle().Foreground(red).Bold(true).Render("💀 GAME OVER"),
+			r.NewStyle().Foreground(fg).Render("Final Score: " + r.NewStyle().Foreground(yellow).Bold(true).Render(fmt.Sprint(m.engine.Score))),
+		}
+		switch {
+		case m.saveErr != "":
+			lines = append(lines, r.NewStyle().Foreground(red).Render("Couldn't save your score: "+m.saveErr))
+		case m.newBest:
+			lines = append(lines, r.NewStyle().Foreground(green).Bold(true).Render("🏆 New best!"))
+		}
+		switch {
+		case m.player == nil && m.engine.Score > 0:
+			lines = append(lines, "", r.NewStyle().Foreground(subtle).Render("Connect with an SSH key to keep your scores."))
+		case m.player != nil && m.player.Nickname() == "" && m.engine.Score > 0:
+			lines = append(lines, "", r.NewStyle().Foreground(subtle).Render("Pick a nickname on the leaderboard to show up there."))
+		}
+		board = inWell(red, lipgloss.DoubleBorder(), lines)
+		foot = "enter to play again  •  esc to go back to menu"
+	case m.paused:
+		board = inWell(yellow, lipgloss.RoundedBorder(), []string{
+			r.NewStyle().Foreground(yellow).Bold(true).Render("⏸  PAUSED"),
+			"",
+			r.NewStyle().Foreground(subtle).Render("p to carry on"),
+		})
+		foot = "p to carry on  •  esc to go back to menu"
+	}
+
+	top, gap := "", "\n"
+	if m.height >= roomyH {
+		top, gap = "\n", "\n\n"
+	}
+	var sb strings.Builder
+	sb.WriteString(top)
+	sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  🧱 Blocks — clear some lines"))
+	sb.WriteString(gap)
+	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel))
+	sb.WriteString("\n")
+	sb.WriteString(footStyle.Render("  " + foot))
+	return sb.String()
+}
@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/bubbletea"
+
+	"github.com/koossaayy/ssh-portal/internal/about"
+	"github.com/koossaayy/ssh-portal/internal/content"
+	"github.com/koossaayy/ssh-portal/internal/portfolio"
+	"github.com/koossaayy/ssh-portal/internal/scores"
+	"github.com/koossaayy/ssh-portal/internal/servers"
+	"github.com/koossaayy/ssh-portal/internal/snake"
+)
+
+// SchemaVersion is bumped whenever a field of the --json output is renamed
+// or removed. Adding fields does not bump it.
+const SchemaVersion = 1
+
+// Document is the top level of every --json answer; exactly one of the
+// section fields is set. The lists are pointers so that an empty one still
+// comes out as [] while the sections nobody asked for are left out.
+type Document struct {
+	SchemaVersion int                  `json:"schema_version"`
+	About         *about.About         `json:"about,omitempty"`
+	Projects      *[]portfolio.Project `json:"projects,omitempty"`
+	Servers       *[]servers.Server    `json:"servers,omitempty"`
+}
+
+type command struct {
+	name string
+	desc string
+	run  func(w io.Writer, st styles, in input) error
+	// doc fills in the command's section of the --json Document; nil when
+	// the command has no JSON form.
+	doc func(d *Document, c *content.Content)
+	// interactive commands open in the TUI instead when the client asked
+	// for a terminal; run is the plain-text fallback.
+	interactive bool
+}
+
+// input is what a command has to work with.
+type input struct {
+	content  *content.Content
+	scores   *scores.Store
+	args     []string
+	download bool
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"about", "Who is this mysterious person?", runAbout, func(d *Document, c *content.Content) {
+			d.About = &c.About
+		}, false},
+		{"projects", "Projects, work, and cool stuff", runProjects, func(d *Document, c *content.Content) {
+			projects := append([]portfolio.Project{}, c.Projects...)
+			d.Projects = &projects
+		}, false},
+		{"servers", "The machines of the realm", runServers, func(d *Document, c *content.Content) {
+			list := append([]servers.Server{}, c.Servers...)
+			d.Servers = &list
+		}, false},
+		{name: "replay", desc: "Watch a saved Snake game: replay <id>", run: runReplay, interactive: true},
+		{"help", "This list", runHelp, nil, false},
+	}
+}
+
+// Middleware answers `ssh host <command>` with plain text and exits, so the
+// portal can be scripted and piped. Sessions without a command fall through
+// to next, which starts the TUI, and so do interactive commands run with a
+// terminal. It must sit after bubbletea.Middleware in wish.WithMiddleware
+// so it runs first.
+func Middleware(store *content.Store, sc *scores.Store) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			args := s.Command()
+			if len(args) == 0 {
+				next(s)
+				return
+			}
+
+			cmd, ok := lookup(args[0])
+			if !ok {
+				wish.Fatalf(s, "ssh-portal: unknown command %q, try \"help\"\n", args[0])
+				return
+			}
+			fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+			fs.SetOutput(s.Stderr())
+			asJSON := fs.Bool("json", false, "print a JSON document instead of text")
+			download := fs.Bool("download", false, "write the replay file instead of describing it")
+			rest, err := parse(fs, args[1:])
+			if err != nil {
+				_ = s.Exit(2)
+				return
+			}
+			if _, _, isPty := s.Pty(); cmd.interactive && isPty && !*download {
+				next(s)
+				return
+			}
+
+			c := store.Current()
+			switch {
+			case *asJSON && cmd.doc == nil:
+				err = fmt.Errorf("no JSON output available")
+			case *download && cmd.name != "replay":
+				err = fmt.Errorf("nothing to download")
+			case *asJSON:
+				d := Document{SchemaVersion: SchemaVersion}
+				cmd.doc(&d, c)
+				enc := json.NewEncoder(s)
+				enc.SetIndent("", "  ")
+				err = enc.Encode(d)
+			default:
+				err = cmd.run(s, newStyles(bubbletea.MakeRenderer(s)), input{content: c, scores: sc, args: rest, download: *download})
+			}
+			if err != nil {
+				wish.Fatalf(s, "ssh-portal: %s: %v\n", cmd.name, err)
+				return
+			}
+			_ = s.Exit(0)
+		}
+	}
+}
+
+// parse parses flags wherever they are among the arguments, so both
+// `replay <id> --download` and `replay --download <id>` work, and returns
+// the rest.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return rest, nil
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// styles stays deliberately light: bold and a few colors, which the
+// renderer drops entirely when the client has no terminal.
+type styles struct {
+	title  lipgloss.Style
+	label  lipgloss.Style
+	link   lipgloss.Style
+	subtle lipgloss.Style
+}
+
+func newStyles(r *lipgloss.Renderer) styles {
+	return styles{
+		title:  r.NewStyle().Foreground(lipgloss.Color("#FF79C6")).Bold(true),
+		label:  r.NewStyle().Foreground(lipgloss.Color("#F1FA8C")).Bold(true),
+		link:   r.NewStyle().Foreground(lipgloss.Color("#8BE9FD")),
+		subtle: r.NewStyle().Foreground(lipgloss.Color("#6272A4")),
+	}
+}
+
+func runHelp(w io.Writer, st styles, _ input) error {
+	fmt.Fprintln(w, st.title.Render("ssh-portal commands"))
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s %s\n", st.label.Render(fmt.Sprintf("%-10s", c.name)), st.subtle.Render(c.desc))
+	}
+	fmt.Fprintln(w, st.subtle.Render("Add --json to about, projects or servers for machine-readable output."))
+	fmt.Fprintln(w, st.subtle.Render("Run replay with -t to watch it, or add --download to save the file."))
+	fmt.Fprintln(w, st.subtle.Render("Run without a command for the interactive portal."))
+	return nil
+}
+
+func runAbout(w io.Writer, st styles, in input) error {
+	a := in.content.About
+	fmt.Fprintln(w, st.title.Render(a.Greeting))
+	for _, sec := range a.Sections {
+		fmt.Fprintf(w, "\n%s\n%s\n", st.label.Render(sec.Title+":"), sec.Body)
+	}
+	if len(a.Stack) > 0 {
+		labels := make([]string, len(a.Stack))
+		for i, b := range a.Stack {
+			labels[i] = b.Label
+		}
+		fmt.Fprintf(w, "\n%s\n%s\n", st.label.Render("Stack:"), strings.Join(labels, " · "))
+	}
+	if len(a.Links) > 0 {
+		fmt.Fprintf(w, "\n%s\n", st.label.Render("Find me:"))
+		for _, l := range a.Links {
+			fmt.Fprintf(w, "%s %-9s %s\n", l.Icon, l.Label, st.link.Render(l.URL))
+		}
+	}
+	return nil
+}
+
+func runProjects(w io.Writer, st styles, in input) error {
+	for i, p := range in.content.Projects {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s  %s %s\n", st.title.Render(p.Name), p.Emoji, p.Status)
+		fmt.Fprintf(w, "  %s\n", p.Desc)
+		fmt.Fprintf(w, "  %s\n", st.link.Render(p.URL))
+		fmt.Fprintf(w, "  %s\n", st.subtle.Render(strings.Join(p.Tech, ", ")))
+	}
+	return nil
+}
+
+func runServers(w io.Writer, st styles, in input) error {
+	for _, s := range in.content.Servers {
+		fmt.Fprintf(w, "%s %s %s %s\n",
+			st.title.Render(fmt.Sprintf("%-14s", s.Name)),
+			st.link.Render(fmt.Sprintf("%-32s", s.Host)),
+			s.Desc,
+			st.subtle.Render("["+s.Tag+"]"),
+		)
+	}
+	return nil
+}
+
+// runReplay describes a saved game when there's no terminal to play it on,
+// or writes out its file with --download.
+func runReplay(w io.Writer, st styles, in input) error {
+	if len(in.args) != 1 {
+		return fmt.Errorf("usage: replay <id> [--download]")
+	}
+	rec, err := in.scores.Recording(in.args[0])
+	if err != nil {
+		return err
+	}
+	if in.download {
+		_, err := w.Write(rec.Data)
+		return err
+	}
+	var replay snake.Replay
+	if err := replay.UnmarshalBinary(rec.Data); err != nil {
+		return fmt.Errorf("replay %s: %w", rec.ID, err)
+	}
+	fmt.Fprintln(w, st.title.Render("📼 Replay "+rec.ID))
+	fmt.Fprintf(w, "%s %s\n", st.label.Render("Player:"), rec.Player)
+	fmt.Fprintf(w, "%s %s\n", st.label.Render("Mode:  "), replay.Mode)
+	if len(replay.Levels) > 0 {
+		var names []string
+		for i, l := range replay.Levels {
+			// Levels named after their file have no name of their own.
+			name := l.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			names = append(names, name)
+		}
+		fmt.Fprintf(w, "%s %s\n", st.label.Render("Levels:"), strings.Join(names, ", "))
+	}
+	fmt.Fprintf(w, "%s %d\n", st.label.Render("Score: "), rec.Score)
+	fmt.Fprintf(w, "%s %s\n", st.label.Render("Length:"), rec.Duration.Round(time.Second))
+	fmt.Fprintf(w, "%s %s\n", st.label.Render("Played:"), rec.At.Format("2006-01-02 15:04 MST"))
+	fmt.Fprintln(w, st.subtle.Render("Add -t to ssh to watch it, or --download to save the file."))
+	return nil
+}
@@ -0,0 +1,405 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is prepended to every environment variable the portal reads.
+const EnvPrefix = "SSH_PORTAL_"
+
+// DefaultConfigFile is loaded when present and no -config flag is given.
+const DefaultConfigFile = "ssh-portal.yaml"
+
+// Config holds every knob of the portal. Values are layered in this order,
+// later sources winning: built-in defaults, config file, SSH_PORTAL_*
+// environment variables, command line flags.
+type Config struct {
+	Host        string `yaml:"host"`
+	Port        int    `yaml:"port"`
+	DataDir     string `yaml:"data_dir"`
+	HostKeyPath string `yaml:"host_key_path"`
+	// HostKeyTypes lists extra key types served next to ed25519 for older
+	// clients: "rsa", "ecdsa".
+	HostKeyTypes []string `yaml:"host_key_types"`
+	// HostKeys are extra, already existing private keys to serve. Any key
+	// with a <path>-cert.pub next to it is also offered as a certificate.
+	HostKeys []string `yaml:"host_keys"`
+	// PublicHost and PublicPort are what clients type to reach the portal,
+	// which behind port mappings differs from Host and Port.
+	PublicHost string `yaml:"public_host"`
+	PublicPort int    `yaml:"public_port"`
+	// ContentDir holds projects.yaml, servers.yaml, quotes.yaml and
+	// about.md.
+	ContentDir string `yaml:"content_dir"`
+	// WatchContent reloads ContentDir on change and pushes it to every
+	// connected session.
+	WatchContent bool `yaml:"watch_content"`
+	// ProbeInterval is how often the Server Directory entries are checked
+	// for reachability; 0 turns probing off.
+	ProbeInterval time.Duration `yaml:"probe_interval"`
+	ProbeTimeout  time.Duration `yaml:"probe_timeout"`
+	// IdentifyVisitors asks clients for their public key so sections can
+	// recognise returning visitors. Keyless clients still get in,
+	// anonymously.
+	IdentifyVisitors bool `yaml:"identify_visitors"`
+	// JumpAllowlist is an authorized_keys style file of visitors who may
+	// connect to Server Directory entries through the portal. Empty turns
+	// jumping off.
+	JumpAllowlist string `yaml:"jump_allowlist"`
+	// JumpKeyPath is the portal's client key for logging into targets,
+	// generated when missing.
+	JumpKeyPath string `yaml:"jump_key_path"`
+	// JumpKnownHosts verifies the targets' host keys.
+	JumpKnownHosts string `yaml:"jump_known_hosts"`
+	// JumpForwardAgent lets visitors connecting with ssh -A use and carry
+	// their own agent to the target.
+	JumpForwardAgent bool          `yaml:"jump_forward_agent"`
+	IdleTimeout      time.Duration `yaml:"idle_timeout"`
+	MaxTimeout       time.Duration `yaml:"max_timeout"`
+
+	// File is the config file that was actually loaded, empty if none.
+	File string `yaml:"-"`
+	// Command holds the positional arguments left after flags, e.g.
+	// ["known-hosts"].
+	Command []string `yaml:"-"`
+}
+
+func Default() Config {
+	return Config{
+		Host:             "0.0.0.0",
+		Port:             2222,
+		DataDir:          ".",
+		ContentDir:       "content",
+		WatchContent:     true,
+		IdentifyVisitors: true,
+		ProbeInterval:    30 * time.Second,
+		ProbeTimeout:     3 * time.Second,
+		IdleTimeout:      30 * time.Minute,
+	}
+}
+
+// Addr is the host:port the SSH server listens on.
+func (c Config) Addr() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// KeyPath returns the host key path, falling back to one inside DataDir.
+func (c Config) KeyPath() string {
+	if c.HostKeyPath != "" {
+		return c.HostKeyPath
+	}
+	return filepath.Join(c.DataDir, ".ssh", "id_ed25519")
+}
+
+// ScoresPath is the score store inside DataDir.
+func (c Config) ScoresPath() string {
+	return filepath.Join(c.DataDir, "scores.db")
+}
+
+// JumpKey returns the jump client key path, falling back to one inside
+// DataDir.
+func (c Config) JumpKey() string {
+	if c.JumpKeyPath != "" {
+		return c.JumpKeyPath
+	}
+	return filepath.Join(c.DataDir, ".ssh", "id_jump_ed25519")
+}
+
+// JumpKnownHostsFile returns the known_hosts file for jump targets, falling
+// back to one inside DataDir.
+func (c Config) JumpKnownHostsFile() string {
+	if c.JumpKnownHosts != "" {
+		return c.JumpKnownHosts
+	}
+	return filepath.Join(c.DataDir, ".ssh", "known_hosts")
+}
+
+// PublicAddr is the host and port clients type to reach the portal.
+func (c Config) PublicAddr() (string, int) {
+	host := c.PublicHost
+	if host == "" {
+		host = c.Host
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			host = "localhost"
+		}
+	}
+	port := c.PublicPort
+	if port == 0 {
+		port = c.Port
+	}
+	return host, port
+}
+
+// KnownHostsName is the host pattern clients store in known_hosts.
+func (c Config) KnownHostsName() string {
+	host, port := c.PublicAddr()
+	if port == 22 {
+		return host
+	}
+	return fmt.Sprintf("[%s]:%d", host, port)
+}
+
+// SSHCommand is how visitors connect, e.g. "ssh -p 2222 ssh.example.com".
+func (c Config) SSHCommand() string {
+	host, port := c.PublicAddr()
+	if port == 22 {
+		return "ssh " + host
+	}
+	return fmt.Sprintf("ssh -p %d %s", port, host)
+}
+
+// Load builds the configuration from args (usually os.Args[1:]) and the
+// process environment.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("ssh-portal", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var (
+		file        = fs.String("config", "", "path to a YAML config file")
+		host        = fs.String("host", cfg.Host, "address to listen on")
+		port        = fs.Int("port", cfg.Port, "port to listen on")
+		dataDir     = fs.String("data-dir", cfg.DataDir, "directory for host keys and persistent data")
+		hostKey     = fs.String("host-key", "", "path to the ed25519 host key (default <data-dir>/.ssh/id_ed25519)")
+		keyTypes    = fs.String("host-key-types", "", "comma separated extra host key types to serve: rsa, ecdsa")
+		hostKeys    = fs.String("host-keys", "", "comma separated paths of extra existing host keys")
+		publicHost  = fs.String("public-host", "", "hostname clients connect to")
+		publicPort  = fs.Int("public-port", 0, "port clients connect to (default -port)")
+		contentDir  = fs.String("content-dir", cfg.ContentDir, "directory with projects, servers, quotes and about content")
+		watch       = fs.Bool("watch-content", cfg.WatchContent, "reload content when files in -content-dir change")
+		probeEvery  = fs.Duration("probe-interval", cfg.ProbeInterval, "how often to check Server Directory entries, 0 to disable")
+		probeTO     = fs.Duration("probe-timeout", cfg.ProbeTimeout, "connect and banner timeout for each check")
+		identify    = fs.Bool("identify-visitors", cfg.IdentifyVisitors, "recognise visitors by their public key")
+		jumpAllow   = fs.String("jump-allowlist", "", "authorized_keys style file of visitors who may jump to servers, empty to disable")
+		jumpKey     = fs.String("jump-key", "", "client key the portal logs into servers with (default <data-dir>/.ssh/id_jump_ed25519)")
+		jumpKnown   = fs.String("jump-known-hosts", "", "known_hosts file for servers (default <data-dir>/.ssh/known_hosts)")
+		jumpAgent   = fs.Bool("jump-forward-agent", cfg.JumpForwardAgent, "use and forward the visitor's agent when they connect with ssh -A")
+		idleTimeout = fs.Duration("idle-timeout", cfg.IdleTimeout, "disconnect idle sessions after this long, 0 to disable")
+		maxTimeout  = fs.Duration("max-timeout", cfg.MaxTimeout, "hard limit on session length, 0 to disable")
+	)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fs.PrintDefaults()
+		}
+		return cfg, err
+	}
+
+	path, explicit := *file, *file != ""
+	if !explicit {
+		if v, ok := os.LookupEnv(EnvPrefix + "CONFIG"); ok && v != "" {
+			path, explicit = v, true
+		} else {
+			path = DefaultConfigFile
+		}
+	}
+	if err := cfg.readFile(path, explicit); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return cfg, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "host":
+			cfg.Host = *host
+		case "port":
+			cfg.Port = *port
+		case "data-dir":
+			cfg.DataDir = *dataDir
+		case "host-key":
+			cfg.HostKeyPath = *hostKey
+		case "host-key-types":
+			cfg.HostKeyTypes = splitList(*keyTypes)
+		case "host-keys":
+			cfg.HostKeys = splitList(*hostKeys)
+		case "public-host":
+			cfg.PublicHost = *publicHost
+		case "public-port":
+			cfg.PublicPort = *publicPort
+		case "content-dir":
+			cfg.ContentDir = *contentDir
+		case "watch-content":
+			cfg.WatchContent = *watch
+		case "probe-interval":
+			cfg.ProbeInterval = *probeEvery
+		case "probe-timeout":
+			cfg.ProbeTimeout = *probeTO
+		case "identify-visitors":
+			cfg.IdentifyVisitors = *identify
+		case "jump-allowlist":
+			cfg.JumpAllowlist = *jumpAllow
+		case "jump-key":
+			cfg.JumpKeyPath = *jumpKey
+		case "jump-known-hosts":
+			cfg.JumpKnownHosts = *jumpKnown
+		case "jump-forward-agent":
+			cfg.JumpForwardAgent = *jumpAgent
+		case "idle-timeout":
+			cfg.IdleTimeout = *idleTimeout
+		case "max-timeout":
+			cfg.MaxTimeout = *maxTimeout
+		}
+	})
+
+	cfg.Command = fs.Args()
+
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) readFile(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("config: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	c.File = path
+	return nil
+}
+
+func (c *Config) applyEnv() error {
+	var errs []error
+	str := func(name string, dst *string) {
+		if v, ok := os.LookupEnv(EnvPrefix + name); ok {
+			*dst = v
+		}
+	}
+	num := func(name string, dst *int) {
+		if v, ok := os.LookupEnv(EnvPrefix + name); ok {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s%s: %q is not a number", EnvPrefix, name, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	boolean := func(name string, dst *bool) {
+		if v, ok := os.LookupEnv(EnvPrefix + name); ok {
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s%s: %q is not a boolean (use true or false)", EnvPrefix, name, v))
+				return
+			}
+			*dst = b
+		}
+	}
+	list := func(name string, dst *[]string) {
+		if v, ok := os.LookupEnv(EnvPrefix + name); ok {
+			*dst = splitList(v)
+		}
+	}
+	dur := func(name string, dst *time.Duration) {
+		if v, ok := os.LookupEnv(EnvPrefix + name); ok {
+			d, err := time.ParseDuration(strings.TrimSpace(v))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s%s: %q is not a duration (try 30s, 5m, 1h)", EnvPrefix, name, v))
+				return
+			}
+			*dst = d
+		}
+	}
+
+	str("HOST", &c.Host)
+	num("PORT", &c.Port)
+	str("DATA_DIR", &c.DataDir)
+	str("HOST_KEY_PATH", &c.HostKeyPath)
+	list("HOST_KEY_TYPES", &c.HostKeyTypes)
+	list("HOST_KEYS", &c.HostKeys)
+	str("PUBLIC_HOST", &c.PublicHost)
+	num("PUBLIC_PORT", &c.PublicPort)
+	str("CONTENT_DIR", &c.ContentDir)
+	boolean("WATCH_CONTENT", &c.WatchContent)
+	dur("PROBE_INTERVAL", &c.ProbeInterval)
+	dur("PROBE_TIMEOUT", &c.ProbeTimeout)
+	boolean("IDENTIFY_VISITORS", &c.IdentifyVisitors)
+	str("JUMP_ALLOWLIST", &c.JumpAllowlist)
+	str("JUMP_KEY_PATH", &c.JumpKeyPath)
+	str("JUMP_KNOWN_HOSTS", &c.JumpKnownHosts)
+	boolean("JUMP_FORWARD_AGENT", &c.JumpForwardAgent)
+	dur("IDLE_TIMEOUT", &c.IdleTimeout)
+	dur("MAX_TIMEOUT", &c.MaxTimeout)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Validate reports every problem at once so a broken deploy needs one fix
+// round, not one per field.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port must be between 1 and 65535, got %d", c.Port))
+	}
+	if c.PublicPort < 0 || c.PublicPort > 65535 {
+		errs = append(errs, fmt.Errorf("public_port must be between 1 and 65535, or 0 for the same as port, got %d", c.PublicPort))
+	}
+	if c.Host != "" && net.ParseIP(c.Host) == nil && strings.ContainsAny(c.Host, " /:") {
+		errs = append(errs, fmt.Errorf("host %q is not a valid address", c.Host))
+	}
+	if c.DataDir == "" {
+		errs = append(errs, errors.New("data_dir must not be empty"))
+	}
+	for _, t := range c.HostKeyTypes {
+		switch t {
+		case "rsa", "ecdsa":
+		case "ed25519":
+			errs = append(errs, errors.New("host_key_types: ed25519 is always served, list only rsa or ecdsa"))
+		default:
+			errs = append(errs, fmt.Errorf("host_key_types: unknown key type %q (want rsa or ecdsa)", t))
+		}
+	}
+	if c.ContentDir == "" {
+		errs = append(errs, errors.New("content_dir must not be empty"))
+	}
+	if c.ProbeInterval < 0 {
+		errs = append(errs, fmt.Errorf("probe_interval must not be negative, got %s", c.ProbeInterval))
+	}
+	if c.ProbeInterval > 0 && c.ProbeTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("probe_timeout must be positive, got %s", c.ProbeTimeout))
+	}
+	if c.IdleTimeout < 0 {
+		errs = append(errs, fmt.Errorf("idle_timeout must not be negative, got %s", c.IdleTimeout))
+	}
+	if c.MaxTimeout < 0 {
+		errs = append(errs, fmt.Errorf("max_timeout must not be negative, got %s", c.MaxTimeout))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile puts a config file with body in a temporary directory.
+func writeFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ssh-portal.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// load runs Load with env set and a -config flag for file, if any.
+func load(t *testing.T, file string, env map[string]string, args ...string) (Config, error) {
+	t.Helper()
+	// Keep a ssh-portal.yaml in the working directory out of it.
+	t.Chdir(t.TempDir())
+	for k, v := range env {
+		t.Setenv(EnvPrefix+k, v)
+	}
+	if file != "" {
+		args = append([]string{"-config", writeFile(t, file)}, args...)
+	}
+	return Load(args)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		args  []string
+		port  int
+		types []string
+		idle  time.Duration
+	}{
+		{
+			name: "defaults",
+			port: 2222,
+			idle: 30 * time.Minute,
+		},
+		{
+			name:  "file beats defaults",
+			file:  "port: 3000\nhost_key_types: [rsa]\nidle_timeout: 1m\n",
+			port:  3000,
+			types: []string{"rsa"},
+			idle:  time.Minute,
+		},
+		{
+			name:  "env beats file",
+			file:  "port: 3000\nhost_key_types: [rsa]\nidle_timeout: 1m\n",
+			env:   map[string]string{"PORT": "4000", "HOST_KEY_TYPES": "ecdsa", "IDLE_TIMEOUT": "2m"},
+			port:  4000,
+			types: []string{"ecdsa"},
+			idle:  2 * time.Minute,
+		},
+		{
+			name:  "flags beat env",
+			file:  "port: 3000\nhost_key_types: [rsa]\nidle_timeout: 1m\n",
+			env:   map[string]string{"PORT": "4000", "HOST_KEY_TYPES": "ecdsa", "IDLE_TIMEOUT": "2m"},
+			args:  []string{"-port", "5000", "-host-key-types", "rsa, ecdsa", "-idle-timeout", "3m"},
+			port:  5000,
+			types: []string{"rsa", "ecdsa"},
+			idle:  3 * time.Minute,
+		},
+		{
+			name:  "only what's set is overridden",
+			file:  "port: 3000\nhost_key_types: [rsa]\n",
+			env:   map[string]string{"IDLE_TIMEOUT": "2m"},
+			args:  []string{"-host-key-types", "ecdsa"},
+			port:  3000,
+			types: []string{"ecdsa"},
+			idle:  2 * time.Minute,
+		},
+		{
+			name: "flag set back to the default",
+			env:  map[string]string{"PORT": "4000"},
+			args: []string{"-port", "2222"},
+			port: 2222,
+			idle: 30 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, tt.file, tt.env, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Port != tt.port || !reflect.DeepEqual(cfg.HostKeyTypes, tt.types) || cfg.IdleTimeout != tt.idle {
+				t.Errorf("port %d, host_key_types %q, idle_timeout %s; want %d, %q, %s",
+					cfg.Port, cfg.HostKeyTypes, cfg.IdleTimeout, tt.port, tt.types, tt.idle)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(EnvPrefix+"CONFIG", writeFile(t, "port: 3000\n"))
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 3000 {
+		t.Errorf("port %d, want 3000 from the file in %sCONFIG", cfg.Port, EnvPrefix)
+	}
+
+	cfg, err = Load([]string{"-config", writeFile(t, "port: 4000\n")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 4000 {
+		t.Errorf("port %d, want 4000 from the -config file", cfg.Port)
+	}
+}
+
+func TestLoadPublicPortZero(t *testing.T) {
+	cfg, err := load(t, "port: 3000\npublic_port: 0\n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, port := cfg.PublicAddr(); port != 3000 {
+		t.Errorf("public port %d, want port 3000", port)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want []string
+	}{
+		{
+			name: "every field at once",
+			file: "port: 0\npublic_port: -1\ndata_dir: \"\"\nprobe_interval: -1s\n",
+			want: []string{
+				"port must be between 1 and 65535, got 0",
+				"public_port must be between 1 and 65535, or 0 for the same as port, got -1",
+				"data_dir must not be empty",
+				"probe_interval must not be negative, got -1s",
+			},
+		},
+		{
+			name: "public_port too high",
+			args: []string{"-public-port", "70000"},
+			want: []string{"public_port must be between 1 and 65535, or 0 for the same as port, got 70000"},
+		},
+		{
+			name: "host key types",
+			env:  map[string]string{"HOST_KEY_TYPES": "rsa,ed25519,dsa"},
+			want: []string{
+				"host_key_types: ed25519 is always served, list only rsa or ecdsa",
+				`host_key_types: unknown key type "dsa" (want rsa or ecdsa)`,
+			},
+		},
+		{
+			name: "env values",
+			env:  map[string]string{"PORT": "lots", "WATCH_CONTENT": "maybe", "IDLE_TIMEOUT": "soon"},
+			want: []string{
+				`SSH_PORTAL_PORT: "lots" is not a number`,
+				`SSH_PORTAL_WATCH_CONTENT: "maybe" is not a boolean`,
+				`SSH_PORTAL_IDLE_TIMEOUT: "soon" is not a duration`,
+			},
+		},
+		{
+			name: "flags only fix what they set",
+			file: "port: 0\ncontent_dir: \"\"\n",
+			args: []string{"-port", "2222"},
+			want: []string{"content_dir must not be empty"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(t, tt.file, tt.env, tt.args...)
+			if err == nil {
+				t.Fatal("Load succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error doesn't say %q:\n%v", want, err)
+				}
+			}
+			if n := strings.Count(err.Error(), "\n") + 1; n != len(tt.want) {
+				t.Errorf("error has %d lines, want %d:\n%v", n, len(tt.want), err)
+			}
+		})
+	}
+}
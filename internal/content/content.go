@@ -0,0 +1,297 @@
+package content
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/koossaayy/ssh-portal/internal/about"
+	"github.com/koossaayy/ssh-portal/internal/portfolio"
+	"github.com/koossaayy/ssh-portal/internal/servers"
+	"github.com/koossaayy/ssh-portal/internal/snake"
+)
+
+// File names inside the content directory. A missing file leaves its
+// section empty; a file that exists but doesn't parse is an error.
+const (
+	ProjectsFile = "projects.yaml"
+	ServersFile  = "servers.yaml"
+	QuotesFile   = "quotes.yaml"
+	AboutFile    = "about.md"
+)
+
+// LevelsDir holds Snake's levels, one map per .txt file, played in file
+// name order.
+const LevelsDir = "levels"
+
+// Content is everything visitors read, as opposed to how it is drawn.
+type Content struct {
+	Projects []portfolio.Project
+	Servers  []servers.Server
+	Quotes   []string
+	About    about.About
+	Levels   []*snake.Level
+}
+
+// Error points at the file and line an editor should jump to.
+type Error struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Msg)
+}
+
+// Load reads and validates every content file in dir. All problems are
+// reported together.
+func Load(dir string) (*Content, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("content: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("content: %s is not a directory", dir)
+	}
+
+	c := &Content{}
+	var errs []error
+	collect := func(err error) {
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			errs = append(errs, joined.Unwrap()...)
+		} else if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	collect(loadList(dir, ProjectsFile, &c.Projects, func(p portfolio.Project) string {
+		if strings.TrimSpace(p.Name) == "" {
+			return "project needs a name"
+		}
+		return ""
+	}))
+	collect(loadList(dir, ServersFile, &c.Servers, func(s servers.Server) string {
+		switch {
+		case strings.TrimSpace(s.Name) == "":
+			return "server needs a name"
+		case strings.TrimSpace(s.Host) == "":
+			return fmt.Sprintf("server %q needs a host", s.Name)
+		}
+		return ""
+	}))
+	collect(loadList(dir, QuotesFile, &c.Quotes, func(q string) string {
+		if strings.TrimSpace(q) == "" {
+			return "quote is empty"
+		}
+		return ""
+	}))
+	collect(loadAbout(dir, &c.About))
+	collect(loadLevels(dir, &c.Levels))
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("content: %w", errors.Join(errs...))
+	}
+	return c, nil
+}
+
+// loadList decodes a YAML sequence into out, strictly, then runs check on
+// every item so the error can carry the item's line.
+func loadList[T any](dir, name string, out *[]T, check func(T) string) error {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return &Error{File: name, Msg: err.Error()}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return &Error{File: name, Msg: err.Error()}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	seq := doc.Content[0]
+	if seq.Kind != yaml.SequenceNode {
+		return &Error{File: name, Line: seq.Line, Msg: "expected a list of entries"}
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var items []T
+	if err := dec.Decode(&items); err != nil && !errors.Is(err, io.EOF) {
+		return &Error{File: name, Msg: err.Error()}
+	}
+
+	var errs []error
+	for i, item := range items {
+		if msg := check(item); msg != "" {
+			errs = append(errs, &Error{File: name, Line: seq.Content[i].Line, Msg: msg})
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	*out = items
+	return nil
+}
+
+// loadAbout parses about.md: YAML front matter between "---" lines, then a
+// Markdown body where every "## " heading starts a section.
+func loadAbout(dir string, out *about.About) error {
+	data, err := os.ReadFile(filepath.Join(dir, AboutFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return &Error{File: AboutFile, Msg: err.Error()}
+	}
+
+	var (
+		a        about.About
+		front    strings.Builder
+		sections []about.Section
+		sec      *about.Section
+		body     []string
+		errs     []error
+		lineNo   int
+		inFront  bool
+	)
+	flush := func() {
+		if sec != nil {
+			sec.Body = strings.TrimSpace(strings.Join(body, "\n"))
+			sections = append(sections, *sec)
+		}
+		body = nil
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimRight(sc.Text(), "\r")
+		switch {
+		case lineNo == 1 && line == "---":
+			inFront = true
+			// Keep line numbers in YAML errors matching the file.
+			front.WriteString("\n")
+		case inFront && line == "---":
+			inFront = false
+		case inFront:
+			front.WriteString(line + "\n")
+		case strings.HasPrefix(line, "## "):
+			flush()
+			sec = &about.Section{Title: strings.TrimSpace(strings.TrimPrefix(line, "## "))}
+		case sec == nil:
+			if strings.TrimSpace(line) != "" {
+				errs = append(errs, &Error{File: AboutFile, Line: lineNo, Msg: "text before the first \"## \" section heading"})
+			}
+		default:
+			body = append(body, line)
+		}
+	}
+	flush()
+	if inFront {
+		errs = append(errs, &Error{File: AboutFile, Line: lineNo, Msg: "front matter is missing its closing ---"})
+	}
+
+	dec := yaml.NewDecoder(strings.NewReader(front.String()))
+	dec.KnownFields(true)
+	if err := dec.Decode(&a); err != nil && !errors.Is(err, io.EOF) {
+		errs = append(errs, &Error{File: AboutFile, Msg: err.Error()})
+	}
+	a.Sections = append(a.Sections, sections...)
+
+	if strings.TrimSpace(a.Greeting) == "" {
+		errs = append(errs, &Error{File: AboutFile, Line: 1, Msg: "front matter needs a greeting"})
+	}
+	for _, l := range a.Links {
+		if l.Label == "" || l.URL == "" {
+			errs = append(errs, &Error{File: AboutFile, Msg: "every link needs a label and a url"})
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	*out = a
+	return nil
+}
+
+// loadLevels parses every map in the levels directory. A level without a
+// name: line is named after its file, less any leading number.
+func loadLevels(dir string, out *[]*snake.Level) error {
+	paths, err := filepath.Glob(filepath.Join(dir, LevelsDir, "*.txt"))
+	if err != nil {
+		return &Error{File: LevelsDir, Msg: err.Error()}
+	}
+	slices.Sort(paths)
+
+	var (
+		levels []*snake.Level
+		errs   []error
+	)
+	for _, path := range paths {
+		name := LevelsDir + "/" + filepath.Base(path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, &Error{File: name, Msg: err.Error()})
+			continue
+		}
+		l, err := snake.ParseLevel(string(data))
+		if err != nil {
+			var joined interface{ Unwrap() []error }
+			if !errors.As(err, &joined) {
+				errs = append(errs, &Error{File: name, Msg: err.Error()})
+				continue
+			}
+			for _, err := range joined.Unwrap() {
+				var le *snake.LevelError
+				if errors.As(err, &le) {
+					errs = append(errs, &Error{File: name, Line: le.Line, Msg: le.Msg})
+				} else {
+					errs = append(errs, &Error{File: name, Msg: err.Error()})
+				}
+			}
+			continue
+		}
+		if l.Name == "" {
+			l.Name = levelName(filepath.Base(path))
+		}
+		levels = append(levels, l)
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	*out = levels
+	return nil
+}
+
+// levelName turns a file name like "02-the-maze.txt" into "The maze".
+func levelName(file string) string {
+	name := strings.TrimSuffix(file, filepath.Ext(file))
+	name = strings.TrimLeft(name, "0123456789-_ ")
+	name = strings.NewReplacer("-", " ", "_", " ").Replace(name)
+	if name == "" {
+		return file
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,112 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/koossaayy/ssh-portal/internal/pubsub"
+)
+
+// reloadDelay lets an editor finish its write/rename dance before the
+// directory is read again.
+const reloadDelay = 250 * time.Millisecond
+
+// Store holds the live content. Readers always get a complete, validated
+// snapshot; a reload swaps the whole thing at once.
+type Store struct {
+	dir     string
+	cur     atomic.Pointer[Content]
+	updates pubsub.Topic[*Content]
+}
+
+// NewStore loads dir once. It fails when the initial content is invalid,
+// since there is no last good copy to fall back to yet.
+func NewStore(dir string) (*Store, error) {
+	c, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir}
+	s.cur.Store(c)
+	return s, nil
+}
+
+func (s *Store) Current() *Content {
+	return s.cur.Load()
+}
+
+// Subscribe delivers every successfully reloaded snapshot.
+func (s *Store) Subscribe() (<-chan *Content, func()) {
+	return s.updates.Subscribe()
+}
+
+// Reload reads the directory again. On error the current content is kept.
+func (s *Store) Reload() error {
+	c, err := Load(s.dir)
+	if err != nil {
+		return err
+	}
+	s.cur.Store(c)
+	s.updates.Publish(c)
+	return nil
+}
+
+// Watch reloads whenever something in the directory or its levels
+// directory changes, until ctx is done. Broken edits are logged and
+// otherwise ignored.
+func (s *Store) Watch(ctx context.Context) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("content: %w", err)
+	}
+	defer w.Close()
+	if err := w.Add(s.dir); err != nil {
+		return fmt.Errorf("content: watching %s: %w", s.dir, err)
+	}
+	levels := filepath.Join(s.dir, LevelsDir)
+	if err := w.Add(levels); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn("Can't watch the levels directory", "dir", levels, "error", err)
+	}
+
+	timer := time.NewTimer(0)
+	<-timer.C
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			if ev.Name == levels && ev.Has(fsnotify.Create) {
+				// Made after the watch started.
+				_ = w.Add(levels)
+			}
+			timer.Reset(reloadDelay)
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn("Content watcher error", "error", err)
+		case <-timer.C:
+			if err := s.Reload(); err != nil {
+				log.Error("Content reload failed, keeping the last good content", "dir", s.dir, "error", err)
+				continue
+			}
+			c := s.Current()
+			log.Info("📚 Reloaded content", "dir", s.dir, "projects", len(c.Projects), "servers", len(c.Servers), "quotes", len(c.Quotes), "levels", len(c.Levels), "sessions", s.updates.Len())
+		}
+	}
+}
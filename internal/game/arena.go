@@ -0,0 +1,197 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/koossaayy/ssh-portal/internal/snake"
+)
+
+// Arena is a session's view of the multiplayer board: a lobby listing who
+// is playing until the visitor joins, then their snake among the others.
+type Arena struct {
+	renderer *lipgloss.Renderer
+	width    int
+	height   int
+	seat     *Seat
+	name     string
+	state    ArenaState
+	err      string
+}
+
+// NewArena opens the arena for seat; name is what other players see.
+func NewArena(r *lipgloss.Renderer, w, h int, seat *Seat, name string) Arena {
+	return Arena{renderer: r, width: w, height: h, seat: seat, name: name}
+}
+
+func (a Arena) Init() tea.Cmd {
+	return a.seat.Watch()
+}
+
+// Leave takes the visitor's snake off the board; call it when they move
+// on to another section.
+func (a Arena) Leave() {
+	a.seat.Leave()
+}
+
+func (a Arena) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		a.width, a.height = msg.Width, msg.Height
+
+	case arenaMsg:
+		a.state = msg.state
+		if msg.updates != nil {
+			return a, next(msg.updates)
+		}
+
+	case tea.KeyMsg:
+		me, joined := a.state.Snake(a.seat.ID())
+		if joined && me.Alive {
+			if d, ok := keyDirection(msg.String()); ok {
+				a.seat.Steer(d)
+			}
+			return a, nil
+		}
+		switch msg.String() {
+		case "enter", " ":
+			a.err = ""
+			if err := a.seat.Join(a.name); err != nil {
+				a.err = err.Error()
+			}
+		}
+	}
+	return a, nil
+}
+
+func keyDirection(key string) (snake.Dir, bool) {
+	switch key {
+	case "up", "k", "w":
+		return snake.Up, true
+	case "down", "j", "s":
+		return snake.Down, true
+	case "left", "h", "a":
+		return snake.Left, true
+	case "right", "l", "d":
+		return snake.Right, true
+	}
+	return snake.None, false
+}
+
+func (a Arena) View() string {
+	r := a.renderer
+
+	pink   := lipgloss.Color("#FF79C6")
+	cyan   := lipgloss.Color("#8BE9FD")
+	yellow := lipgloss.Color("#F1FA8C")
+	red    := lipgloss.Color("#FF5555")
+	fg     := lipgloss.Color("#F8F8F2")
+	subtle := lipgloss.Color("#6272A4")
+	purple := lipgloss.Color("#9B72CF")
+
+	st := a.state
+	me, joined := st.Snake(a.seat.ID())
+
+	type cell struct {
+		snake int
+		head  bool
+	}
+	cells := map[snake.Point]cell{}
+	for i, s := range st.Snakes {
+		for j, p := range s.Body {
+			cells[p] = cell{snake: i, head: j == 0}
+		}
+	}
+	food := map[snake.Point]bool{}
+	for _, f := range st.Food {
+		food[f] = true
+	}
+
+	var board strings.Builder
+	for y := 0; y < st.H; y++ {
+		for x := 0; x < st.W; x++ {
+			p := snake.Point{X: x, Y: y}
+			c, ok := cells[p]
+			switch {
+			case ok && c.head:
+				s := st.Snakes[c.snake]
+				board.WriteString(r.NewStyle().Foreground(lipgloss.Color(s.Color)).Bold(true).Render("●"))
+			case ok:
+				board.WriteString(r.NewStyle().Foreground(lipgloss.Color(st.Snakes[c.snake].Color)).Render("○"))
+			case food[p]:
+				board.WriteString(r.NewStyle().Foreground(red).Render("❤"))
+			default:
+				board.WriteString(" ")
+			}
+		}
+		if y < st.H-1 {
+			board.WriteString("\n")
+		}
+	}
+	boardBox := r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(purple).
+		Padding(0, 1).
+		Render(board.String())
+
+	var players strings.Builder
+	players.WriteString(r.NewStyle().Foreground(yellow).Bold(true).Render("🐉 PLAYERS"))
+	players.WriteString("\n")
+	if len(st.Snakes) == 0 {
+		players.WriteString("\n")
+		players.WriteString(r.NewStyle().Foreground(subtle).Italic(true).Render("Nobody yet."))
+	}
+	for _, s := range st.Snakes {
+		name := s.Name
+		if len([]rune(name)) > 12 {
+			name = string([]rune(name)[:11]) + "…"
+		}
+		nameStyle := r.NewStyle().Foreground(fg)
+		if s.ID == a.seat.ID() {
+			nameStyle = nameStyle.Bold(true)
+		}
+		status := fmt.Sprintf("%3d", s.Score)
+		if !s.Alive {
+			status = " 💀"
+		}
+		players.WriteString("\n")
+		players.WriteString(r.NewStyle().Foreground(lipgloss.Color(s.Color)).Render("■ "))
+		players.WriteString(nameStyle.Render(fmt.Sprintf("%-12s", name)))
+		players.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render(status))
+	}
+	statsBox := r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(cyan).
+		Padding(1, 2).
+		Width(24).
+		Render(players.String())
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  🐉 Snake Arena — play with everyone online"))
+	sb.WriteString("\n\n  ")
+	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, boardBox, "  ", statsBox))
+	sb.WriteString("\n\n")
+
+	footStyle := r.NewStyle().Foreground(subtle).Italic(true)
+	switch {
+	case a.err != "":
+		sb.WriteString(r.NewStyle().Foreground(red).Render("  " + a.err))
+		sb.WriteString("\n")
+		sb.WriteString(footStyle.Render("  enter to try again  •  esc to go back"))
+	case joined && me.Alive:
+		sb.WriteString(footStyle.Render("  w a s d / ↑ ↓ ← → to steer  •  esc to leave the arena"))
+	case joined:
+		sb.WriteString(r.NewStyle().Foreground(red).Bold(true).Render(fmt.Sprintf("  💀 You %s with %d points.", me.Cause, me.Score)))
+		sb.WriteString("\n")
+		sb.WriteString(footStyle.Render("  enter to rejoin  •  esc to leave the arena"))
+	default:
+		sb.WriteString(r.NewStyle().Foreground(fg).Render(fmt.Sprintf("  You'll play as %s.", a.name)))
+		sb.WriteString("\n")
+		sb.WriteString(footStyle.Render("  enter to join  •  esc to go back"))
+	}
+	return sb.String()
+}
@@ -0,0 +1,438 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/koossaayy/ssh-portal/internal/pubsub"
+	"github.com/koossaayy/ssh-portal/internal/scores"
+	"github.com/koossaayy/ssh-portal/internal/snake"
+)
+
+// arenaColors are handed out to arena players in order; the arena holds as
+// many players as there are colours.
+var arenaColors = []string{"#50FA7B", "#8BE9FD", "#FF79C6", "#F1FA8C", "#FFB86C", "#BD93F9", "#FF5555", "#F8F8F2"}
+
+// ArenaSnake is one player's snake as the hub last moved it.
+type ArenaSnake struct {
+	ID    int
+	Name  string
+	Color string
+	Body  []snake.Point
+	Alive bool
+	Score int
+	// Cause says what killed the snake, once it's dead.
+	Cause string
+
+	dir     snake.Dir
+	nextDir snake.Dir
+}
+
+// ArenaState is a snapshot of the shared board, published after every tick
+// and whenever someone joins or leaves.
+type ArenaState struct {
+	W, H   int
+	Snakes []ArenaSnake
+	Food   []snake.Point
+}
+
+// Snake returns the snake of seat id, if it's in the arena.
+func (st ArenaState) Snake(id int) (ArenaSnake, bool) {
+	for _, s := range st.Snakes {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return ArenaSnake{}, false
+}
+
+// Hub is what sessions' games share: the multiplayer arena, with one board
+// and one tick loop that sessions only send their steering, the solo games
+// being broadcast to spectators, and saved replays.
+type Hub struct {
+	interval time.Duration
+
+	mu     sync.Mutex
+	state  ArenaState
+	nextID int
+	rng    *rand.Rand
+	live   map[int]*liveGame
+
+	// replays is where saved games go, and sshCommand how visitors reach
+	// the portal, for telling them how to share one.
+	replays    *scores.Store
+	sshCommand string
+
+	updates pubsub.Topic[ArenaState]
+	games   pubsub.Topic[[]LiveGame]
+}
+
+func NewHub(replays *scores.Store, sshCommand string) *Hub {
+	return &Hub{
+		interval:   snake.TickInterval,
+		state:      ArenaState{W: 60, H: 25},
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		replays:    replays,
+		sshCommand: sshCommand,
+	}
+}
+
+// Run ticks the arena until ctx is done. Ticks with nobody playing are
+// skipped.
+func (h *Hub) Run(ctx context.Context) {
+	t := time.NewTicker(h.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		h.mu.Lock()
+		if h.step() {
+			h.publish()
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Seat gives one session its place at the hub. The seat leaves the arena,
+// stops broadcasting and stops listening when ctx ends, however the
+// session went away.
+func (h *Hub) Seat(ctx context.Context) *Seat {
+	h.mu.Lock()
+	h.nextID++
+	s := &Seat{hub: h, id: h.nextID}
+	h.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		s.hub.leave(s.id)
+		s.EndBroadcast()
+		s.unsubscribe()
+	}()
+	return s
+}
+
+// snapshot copies the state so subscribers can read it without the lock.
+// Callers hold h.mu.
+func (h *Hub) snapshot() ArenaState {
+	st := ArenaState{W: h.state.W, H: h.state.H, Food: append([]snake.Point(nil), h.state.Food...)}
+	for _, s := range h.state.Snakes {
+		s.Body = append([]snake.Point(nil), s.Body...)
+		st.Snakes = append(st.Snakes, s)
+	}
+	return st
+}
+
+func (h *Hub) publish() {
+	h.updates.Publish(h.snapshot())
+}
+
+func (h *Hub) find(id int) *ArenaSnake {
+	for i := range h.state.Snakes {
+		if h.state.Snakes[i].ID == id {
+			return &h.state.Snakes[i]
+		}
+	}
+	return nil
+}
+
+func (h *Hub) join(id int, name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.find(id)
+	if s == nil {
+		if len(h.state.Snakes) == len(arenaColors) {
+			return fmt.Errorf("the arena is full (%d players), try again in a bit", len(arenaColors))
+		}
+		h.state.Snakes = append(h.state.Snakes, ArenaSnake{ID: id, Color: h.freeColor()})
+		s = &h.state.Snakes[len(h.state.Snakes)-1]
+	}
+	body, dir, ok := h.spawn()
+	if !ok {
+		return fmt.Errorf("no room on the board right now, try again in a bit")
+	}
+	s.Name = name
+	s.Body = body
+	s.dir, s.nextDir = dir, dir
+	s.Alive = true
+	s.Score = 0
+	s.Cause = ""
+	h.refillFood()
+	h.publish()
+	return nil
+}
+
+func (h *Hub) freeColor() string {
+	used := map[string]bool{}
+	for _, s := range h.state.Snakes {
+		used[s.Color] = true
+	}
+	for _, c := range arenaColors {
+		if !used[c] {
+			return c
+		}
+	}
+	return arenaColors[0]
+}
+
+// spawn finds a free row segment for a new three-long snake, heading
+// towards the far side of the board with room in front of it.
+func (h *Hub) spawn() ([]snake.Point, snake.Dir, bool) {
+	occupied := h.occupied()
+	for try := 0; try < 200; try++ {
+		x := 4 + h.rng.Intn(h.state.W-8)
+		y := 1 + h.rng.Intn(h.state.H-2)
+		dir, step := snake.Right, -1
+		if x >= h.state.W/2 {
+			dir, step = snake.Left, 1
+		}
+		free := true
+		for i := -3; i <= 2 && free; i++ {
+			free = !occupied[snake.Point{X: x + i*step, Y: y}]
+		}
+		if free {
+			return []snake.Point{{X: x, Y: y}, {X: x + step, Y: y}, {X: x + 2*step, Y: y}}, dir, true
+		}
+	}
+	return nil, snake.Right, false
+}
+
+func (h *Hub) occupied() map[snake.Point]bool {
+	occupied := map[snake.Point]bool{}
+	for _, s := range h.state.Snakes {
+		for _, p := range s.Body {
+			occupied[p] = true
+		}
+	}
+	for _, f := range h.state.Food {
+		occupied[f] = true
+	}
+	return occupied
+}
+
+// refillFood keeps one piece of food on the board per living snake, or as
+// many as there are free cells for.
+func (h *Hub) refillFood() {
+	want := 0
+	for _, s := range h.state.Snakes {
+		if s.Alive {
+			want++
+		}
+	}
+	want = max(want, 1)
+	if len(h.state.Food) > want {
+		h.state.Food = h.state.Food[:want]
+	}
+	if len(h.state.Food) == want {
+		return
+	}
+	occupied := h.occupied()
+	var free []snake.Point
+	for y := range h.state.H {
+		for x := range h.state.W {
+			if p := (snake.Point{X: x, Y: y}); !occupied[p] {
+				free = append(free, p)
+			}
+		}
+	}
+	for len(h.state.Food) < want && len(free) > 0 {
+		i := h.rng.Intn(len(free))
+		h.state.Food = append(h.state.Food, free[i])
+		free[i] = free[len(free)-1]
+		free = free[:len(free)-1]
+	}
+}
+
+func (h *Hub) steer(id int, d snake.Dir) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s := h.find(id); s != nil && s.Alive && d != s.dir.Opposite() {
+		s.nextDir = d
+	}
+}
+
+func (h *Hub) leave(id int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, s := range h.state.Snakes {
+		if s.ID == id {
+			h.state.Snakes = append(h.state.Snakes[:i], h.state.Snakes[i+1:]...)
+			h.refillFood()
+			h.publish()
+			return
+		}
+	}
+}
+
+// step moves every living snake at once. A snake dies running into a wall
+// or any snake's body, including the tail of a snake that's growing this
+// tick; two heads meeting kill both. It reports whether anything moved.
+// Callers hold h.mu.
+func (h *Hub) step() bool {
+	snakes := h.state.Snakes
+	heads := map[int]snake.Point{}
+	eats := map[int]int{}
+	for i := range snakes {
+		s := &snakes[i]
+		if !s.Alive {
+			continue
+		}
+		s.dir = s.nextDir
+		heads[i] = s.dir.Move(s.Body[0])
+		eats[i] = -1
+		for f, p := range h.state.Food {
+			if p == heads[i] {
+				eats[i] = f
+			}
+		}
+	}
+	if len(heads) == 0 {
+		return false
+	}
+
+	// Tails move out of the way unless their snake is growing.
+	bodies := map[snake.Point]int{}
+	for i, s := range snakes {
+		body := s.Body
+		if _, moving := heads[i]; moving && eats[i] < 0 {
+			body = body[:len(body)-1]
+		}
+		for _, p := range body {
+			bodies[p] = i
+		}
+	}
+
+	dead := map[int]string{}
+	for i, head := range heads {
+		j, hit := bodies[head]
+		switch {
+		case head.X < 0 || head.X >= h.state.W || head.Y < 0 || head.Y >= h.state.H:
+			dead[i] = "hit the wall"
+		case hit && j == i:
+			dead[i] = "bit yourself"
+		case hit:
+			dead[i] = "ran into " + snakes[j].Name
+		}
+		for j, other := range heads {
+			if j != i && other == head {
+				dead[i] = "crashed head-on into " + snakes[j].Name
+			}
+		}
+	}
+
+	eaten := map[int]bool{}
+	for i, head := range heads {
+		s := &snakes[i]
+		if cause, ok := dead[i]; ok {
+			s.Alive = false
+			s.Body = nil
+			s.Cause = cause
+			continue
+		}
+		s.Body = append([]snake.Point{head}, s.Body...)
+		if f := eats[i]; f >= 0 {
+			s.Score++
+			eaten[f] = true
+		} else {
+			s.Body = s.Body[:len(s.Body)-1]
+		}
+	}
+	if len(eaten) > 0 {
+		food := h.state.Food[:0]
+		for f, p := range h.state.Food {
+			if !eaten[f] {
+				food = append(food, p)
+			}
+		}
+		h.state.Food = food
+	}
+	h.refillFood()
+	return true
+}
+
+// Seat is one session's handle on the hub. It only receives updates for
+// what the session is looking at.
+type Seat struct {
+	hub *Hub
+	id  int
+
+	mu   sync.Mutex
+	subs map[string]func()
+}
+
+// subscribed records the unsubscribe func for one kind of subscription,
+// ending the previous one of that kind.
+func (s *Seat) subscribed(kind string, unsubscribe func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs == nil {
+		s.subs = map[string]func(){}
+	}
+	if old := s.subs[kind]; old != nil {
+		old()
+	}
+	s.subs[kind] = unsubscribe
+}
+
+// unsubscribe ends the given kinds of subscription, or all of them.
+func (s *Seat) unsubscribe(kinds ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for kind, unsubscribe := range s.subs {
+		if len(kinds) == 0 || slices.Contains(kinds, kind) {
+			unsubscribe()
+			delete(s.subs, kind)
+		}
+	}
+}
+
+// arenaMsg carries a state to the arena view, along with the subscription
+// it came from so the view can wait for the next one. The first snapshot
+// has no subscription.
+type arenaMsg struct {
+	state   ArenaState
+	updates <-chan ArenaState
+}
+
+// Watch subscribes to the arena and returns the command delivering its
+// current state and the updates after it.
+func (s *Seat) Watch() tea.Cmd {
+	h := s.hub
+	h.mu.Lock()
+	st := h.snapshot()
+	ch, unsubscribe := h.updates.Subscribe()
+	h.mu.Unlock()
+	s.subscribed("arena", unsubscribe)
+	return tea.Batch(func() tea.Msg { return arenaMsg{state: st} }, next(ch))
+}
+
+// next waits for the arena's next state on ch. A closed channel means the
+// seat stopped watching, and yields no message.
+func next(ch <-chan ArenaState) tea.Cmd {
+	return func() tea.Msg {
+		st, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return arenaMsg{state: st, updates: ch}
+	}
+}
+
+func (s *Seat) ID() int { return s.id }
+
+func (s *Seat) Join(name string) error { return s.hub.join(s.id, name) }
+
+func (s *Seat) Steer(d snake.Dir) { s.hub.steer(s.id, d) }
+
+// Leave takes the seat's snake off the board and stops watching.
+func (s *Seat) Leave() {
+	s.hub.leave(s.id)
+	s.unsubscribe("arena")
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/koossaayy/ssh-portal/internal/snake"
+)
+
+// board is every cell of a w×h board but those in except.
+func board(w, h int, except ...snake.Point) []snake.Point {
+	var out []snake.Point
+	for y := range h {
+		for x := range w {
+			p := snake.Point{X: x, Y: y}
+			skip := false
+			for _, e := range except {
+				skip = skip || e == p
+			}
+			if !skip {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// refill runs refillFood, failing the test if it doesn't return.
+func refill(t *testing.T, h *Hub) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		h.mu.Lock()
+		h.refillFood()
+		h.mu.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("refillFood didn't return")
+	}
+}
+
+func TestRefillFoodFullBoard(t *testing.T) {
+	h := NewHub(nil, "")
+	h.state = ArenaState{W: 4, H: 3, Snakes: []ArenaSnake{{ID: 1, Alive: true, Body: board(4, 3)}}}
+	refill(t, h)
+	if len(h.state.Food) != 0 {
+		t.Errorf("Food = %v on a full board", h.state.Food)
+	}
+}
+
+func TestRefillFoodTakesWhatsFree(t *testing.T) {
+	free := []snake.Point{{X: 0, Y: 0}, {X: 3, Y: 1}}
+	h := NewHub(nil, "")
+	h.state = ArenaState{W: 4, H: 3, Snakes: []ArenaSnake{
+		{ID: 1, Alive: true, Body: board(4, 3, free...)},
+		{ID: 2, Alive: true},
+		{ID: 3, Alive: true},
+	}}
+	refill(t, h)
+	if len(h.state.Food) != 2 || h.state.Food[0] == h.state.Food[1] {
+		t.Fatalf("Food = %v, want both free cells once each", h.state.Food)
+	}
+	for _, f := range h.state.Food {
+		if f != free[0] && f != free[1] {
+			t.Errorf("food at %v, which isn't free", f)
+		}
+	}
+}
+
+func TestRefillFoodKeepsWhatsThere(t *testing.T) {
+	h := NewHub(nil, "")
+	h.state = ArenaState{W: 10, H: 10, Food: []snake.Point{{X: 5, Y: 5}}, Snakes: []ArenaSnake{
+		{ID: 1, Alive: true, Body: []snake.Point{{X: 1, Y: 1}}},
+		{ID: 2, Alive: true, Body: []snake.Point{{X: 2, Y: 2}}},
+	}}
+	refill(t, h)
+	if len(h.state.Food) != 2 || h.state.Food[0] != (snake.Point{X: 5, Y: 5}) {
+		t.Fatalf("Food = %v, want the old piece and one more", h.state.Food)
+	}
+	for _, f := range h.state.Food[1:] {
+		if f == (snake.Point{X: 5, Y: 5}) || f == (snake.Point{X: 1, Y: 1}) || f == (snake.Point{X: 2, Y: 2}) {
+			t.Errorf("new food at %v, which is taken", f)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/koossaayy/ssh-portal/internal/scores"
+)
+
+// leaderboardSize is how many players each tab lists.
+const leaderboardSize = 10
+
+// Board is one list of scores on the leaderboard, such as a game mode.
+type Board struct {
+	Name string
+	// Key is the game the scores are kept under.
+	Key string
+	// Day boards only have one day's scores, so they aren't split into
+	// periods.
+	Day bool
+	// Unit follows each score, such as "%".
+	Unit string
+}
+
+// Leaderboard shows the best score per player, all-time, this week and
+// today, on one of its boards. Boards can be of different games; title
+// names the game when they're all of one.
+type Leaderboard struct {
+	renderer *lipgloss.Renderer
+	width    int
+	height   int
+	store    *scores.Store
+	player   *scores.Player
+	title    string
+	boards   []Board
+	board    int
+	period   int
+	entries  []scores.Entry
+	err      error
+	best     int
+	nick     string
+	naming   bool
+	name     nameInput
+}
+
+func NewLeaderboard(r *lipgloss.Renderer, w, h int, store *scores.Store, player *scores.Player, title string, boards []Board) Leaderboard {
+	l := Leaderboard{renderer: r, width: w, height: h, store: store, player: player, title: title, boards: boards}
+	l.load()
+	return l
+}
+
+// Show switches to the board with key, if there is one.
+func (l Leaderboard) Show(key string) Leaderboard {
+	for i, b := range l.boards {
+		if b.Key == key {
+			l.board = i
+			l.load()
+		}
+	}
+	return l
+}
+
+func (l *Leaderboard) load() {
+	var since time.Time
+	if !l.boards[l.board].Day {
+		since = scores.Periods[l.period].Since(time.Now())
+	}
+	l.entries, l.err = l.store.Top(l.boards[l.board].Key, since, leaderboardSize)
+	l.best = l.player.Best(l.boards[l.board].Key)
+	l.nick = l.player.Nickname()
+}
+
+// Typing reports whether keys are going into the nickname field.
+func (l Leaderboard) Typing() bool {
+	return l.naming
+}
+
+func (l Leaderboard) Init() tea.Cmd { return nil }
+
+func (l Leaderboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		l.width, l.height = size.Width, size.Height
+		return l, nil
+	}
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return l, nil
+	}
+	if l.naming {
+		done, cancel := l.name.update(key)
+		if cancel || (done && l.name.save(l.player)) {
+			l.naming = false
+			l.load()
+		}
+		return l, nil
+	}
+	switch key.String() {
+	case "left", "h", "shift+tab":
+		l.period = (l.period + len(scores.Periods) - 1) % len(scores.Periods)
+		l.load()
+	case "right", "l", "tab":
+		l.period = (l.period + 1) % len(scores.Periods)
+		l.load()
+	case "up", "k":
+		l.board = (l.board + len(l.boards) - 1) % len(l.boards)
+		l.load()
+	case "down", "j":
+		l.board = (l.board + 1) % len(l.boards)
+		l.load()
+	case "n":
+		if l.player != nil {
+			l.naming = true
+			l.name = nameInput{value: []rune(l.nick)}
+		}
+	}
+	return l, nil
+}
+
+func (l Leaderboard) View() string {
+	r := l.renderer
+
+	pink   := lipgloss.Color("#FF79C6")
+	cyan   := lipgloss.Color("#8BE9FD")
+	yellow := lipgloss.Color("#F1FA8C")
+	fg     := lipgloss.Color("#F8F8F2")
+	subtle := lipgloss.Color("#6272A4")
+	purple := lipgloss.Color("#9B72CF")
+	red    := lipgloss.Color("#FF5555")
+
+	titleStyle := r.NewStyle().Foreground(pink).Bold(true)
+	footStyle  := r.NewStyle().Foreground(subtle).Italic(true)
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	title := "  🏆 Leaderboard"
+	if l.title != "" {
+		title += " — " + l.title
+	}
+	sb.WriteString(titleStyle.Render(title))
+	sb.WriteString("\n\n  ")
+
+	day := l.boards[l.board].Day
+	for i, p := range scores.Periods {
+		tab := r.NewStyle().Padding(0, 1).Foreground(subtle)
+		switch {
+		case day:
+			tab = tab.Faint(true)
+		case i == l.period:
+			tab = tab.Foreground(lipgloss.Color("#282A36")).Background(purple).Bold(true)
+		}
+		sb.WriteString(tab.Render(p.String()))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+	// There are too many boards to list side by side, so only the one
+	// shown is named.
+	if len(l.boards) > 1 {
+		sb.WriteString("  ")
+		sb.WriteString(r.NewStyle().Padding(0, 1).Foreground(cyan).Bold(true).Render("‹ " + l.boards[l.board].Name + " ›"))
+		sb.WriteString(r.NewStyle().Foreground(subtle).Render(fmt.Sprintf("%d/%d", l.board+1, len(l.boards))))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	switch {
+	case l.err != nil:
+		sb.WriteString(r.NewStyle().Foreground(red).Render("  Couldn't read the scores: " + l.err.Error()))
+		sb.WriteString("\n")
+	case len(l.entries) == 0:
+		sb.WriteString(r.NewStyle().Foreground(subtle).Italic(true).Render("  No scores yet. Be the first!"))
+		sb.WriteString("\n")
+	}
+	medals := []string{"🥇", "🥈", "🥉"}
+	for i, e := range l.entries {
+		rank := fmt.Sprintf("%2d.", i+1)
+		if i < len(medals) {
+			rank = medals[i] + " "
+		}
+		nameStyle := r.NewStyle().Foreground(fg)
+		if e.Fingerprint == l.player.Fingerprint() {
+			nameStyle = r.NewStyle().Foreground(yellow).Bold(true)
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s %s  %s\n",
+			rank,
+			nameStyle.Render(fmt.Sprintf("%-*s", scores.MaxNickname+2, e.Name())),
+			r.NewStyle().Foreground(cyan).Bold(true).Render(fmt.Sprintf("%5d%-1s", e.Score, l.boards[l.board].Unit)),
+			r.NewStyle().Foreground(subtle).Render(e.At.Format("2006-01-02")),
+		))
+	}
+
+	var hints []string
+	if !day {
+		hints = append(hints, "← → / tab to switch")
+	}
+	if len(l.boards) > 1 {
+		hints = append(hints, "↑ ↓ board")
+	}
+	sb.WriteString("\n")
+	switch {
+	case l.naming:
+		sb.WriteString(r.NewStyle().Foreground(cyan).Render("  Nickname: "))
+		sb.WriteString(l.name.view(r))
+		sb.WriteString("\n\n")
+		sb.WriteString(footStyle.Render("  enter to save  •  esc to cancel"))
+	case l.player == nil:
+		sb.WriteString(r.NewStyle().Foreground(subtle).Render("  Connect with an SSH key to get on the board."))
+		sb.WriteString("\n\n")
+		sb.WriteString(footStyle.Render("  " + strings.Join(append(hints, "esc to go back"), "  •  ")))
+	default:
+		nick := l.nick
+		if nick == "" {
+			nick = "no nickname yet"
+		}
+		sb.WriteString(r.NewStyle().Foreground(subtle).Render(fmt.Sprintf("  You: %s  •  best %d%s", nick, l.best, l.boards[l.board].Unit)))
+		sb.WriteString("\n\n")
+		sb.WriteString(footStyle.Render("  " + strings.Join(append(hints, "n to change nickname", "esc to go back"), "  •  ")))
+	}
+	return sb.String()
+}
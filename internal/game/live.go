@@ -0,0 +1,155 @@
+package game
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/koossaayy/ssh-portal/internal/pubsub"
+	"github.com/koossaayy/ssh-portal/internal/snake"
+)
+
+// LiveGame is a solo game in progress, as listed for spectators.
+type LiveGame struct {
+	ID     int
+	Player string
+	Score  int
+	Over   bool
+}
+
+// SoloState is everything a spectator needs to draw someone's solo game.
+type SoloState struct {
+	LiveGame
+	High int
+	Game snake.State
+	// Left is set on the last state of a game whose player went away.
+	Left bool
+}
+
+type liveGame struct {
+	state   SoloState
+	updates pubsub.Topic[SoloState]
+}
+
+// broadcast makes st the latest state of seat id's game. Publishing never
+// waits on spectators, so watching can't slow the player down.
+func (h *Hub) broadcast(id int, st SoloState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.live == nil {
+		h.live = map[int]*liveGame{}
+	}
+	g, ok := h.live[id]
+	if !ok {
+		g = &liveGame{}
+		h.live[id] = g
+	}
+	st.ID = id
+	listed := ok && g.state.LiveGame == st.LiveGame
+	g.state = st
+	g.updates.Publish(st)
+	if !listed {
+		h.games.Publish(h.liveGames())
+	}
+}
+
+func (h *Hub) endBroadcast(id int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	g, ok := h.live[id]
+	if !ok {
+		return
+	}
+	delete(h.live, id)
+	g.state.Left = true
+	g.updates.Publish(g.state)
+	h.games.Publish(h.liveGames())
+}
+
+// liveGames lists the games being played, best score first. Callers hold
+// h.mu.
+func (h *Hub) liveGames() []LiveGame {
+	games := make([]LiveGame, 0, len(h.live))
+	for _, g := range h.live {
+		games = append(games, g.state.LiveGame)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if games[i].Score != games[j].Score {
+			return games[i].Score > games[j].Score
+		}
+		return games[i].ID < games[j].ID
+	})
+	return games
+}
+
+// liveListMsg and liveGameMsg carry spectator updates along with the
+// subscription they came from, like arenaMsg.
+type liveListMsg struct {
+	games   []LiveGame
+	updates <-chan []LiveGame
+}
+
+type liveGameMsg struct {
+	state   SoloState
+	updates <-chan SoloState
+}
+
+// Broadcast publishes the seat's solo game to spectators.
+func (s *Seat) Broadcast(st SoloState) { s.hub.broadcast(s.id, st) }
+
+// EndBroadcast tells spectators the seat's player stopped playing.
+func (s *Seat) EndBroadcast() { s.hub.endBroadcast(s.id) }
+
+// WatchGames subscribes to the list of games other sessions are playing.
+func (s *Seat) WatchGames() tea.Cmd {
+	h := s.hub
+	h.mu.Lock()
+	games := h.liveGames()
+	ch, unsubscribe := h.games.Subscribe()
+	h.mu.Unlock()
+	s.subscribed("games", unsubscribe)
+	return tea.Batch(func() tea.Msg { return liveListMsg{games: games} }, nextGames(ch))
+}
+
+// Spectate subscribes to game id instead of any game watched before. It
+// fails when the game has already ended.
+func (s *Seat) Spectate(id int) (tea.Cmd, bool) {
+	h := s.hub
+	h.mu.Lock()
+	g, ok := h.live[id]
+	if !ok {
+		h.mu.Unlock()
+		return nil, false
+	}
+	st := g.state
+	ch, unsubscribe := g.updates.Subscribe()
+	h.mu.Unlock()
+	s.subscribed("solo", unsubscribe)
+	return tea.Batch(func() tea.Msg { return liveGameMsg{state: st} }, nextSolo(ch)), true
+}
+
+// StopSpectating ends the subscription to the watched game.
+func (s *Seat) StopSpectating() { s.unsubscribe("solo") }
+
+// StopWatchingGames ends every spectator subscription.
+func (s *Seat) StopWatchingGames() { s.unsubscribe("games", "solo") }
+
+func nextGames(ch <-chan []LiveGame) tea.Cmd {
+	return func() tea.Msg {
+		games, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return liveListMsg{games: games, updates: ch}
+	}
+}
+
+func nextSolo(ch <-chan SoloState) tea.Cmd {
+	return func() tea.Msg {
+		st, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return liveGameMsg{state: st, updates: ch}
+	}
+}
@@ -0,0 +1,65 @@
+package game
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/koossaayy/ssh-portal/internal/scores"
+)
+
+// nameInput is the one-line field visitors type their leaderboard
+// nickname into.
+type nameInput struct {
+	value []rune
+	err   string
+}
+
+// update edits the field; it reports enter as done and esc as cancel.
+func (n *nameInput) update(k tea.KeyMsg) (done, cancel bool) {
+	switch k.Type {
+	case tea.KeyEnter:
+		return true, false
+	case tea.KeyEsc:
+		return false, true
+	case tea.KeyBackspace:
+		if len(n.value) > 0 {
+			n.value = n.value[:len(n.value)-1]
+		}
+	case tea.KeySpace:
+		n.add(' ')
+	case tea.KeyRunes:
+		for _, r := range k.Runes {
+			n.add(r)
+		}
+	}
+	return false, false
+}
+
+func (n *nameInput) add(r rune) {
+	if len(n.value) < scores.MaxNickname {
+		n.value = append(n.value, r)
+	}
+}
+
+// save stores the nickname, keeping the error for the view when it's
+// refused.
+func (n *nameInput) save(p *scores.Player) bool {
+	if err := p.SetNickname(string(n.value)); err != nil {
+		n.err = err.Error()
+		return false
+	}
+	n.err = ""
+	return true
+}
+
+func (n nameInput) view(r *lipgloss.Renderer) string {
+	field := r.NewStyle().
+		Foreground(lipgloss.Color("#F8F8F2")).
+		Background(lipgloss.Color("#44475A")).
+		Width(scores.MaxNickname + 1).
+		Render(string(n.value) + "▏")
+	if n.err != "" {
+		field += "  " + r.NewStyle().Foreground(lipgloss.Color("#FF5555")).Render(n.err)
+	}
+	return field
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/koossaayy/ssh-portal/internal/content"
+	"github.com/koossaayy/ssh-portal/internal/scores"
+)
+
+// Game is a game being played from the Games menu. It gets every message
+// while it's open, keys included, and the content when it's reloaded.
+type Game interface {
+	tea.Model
+	// Done reports whether the player has asked to leave.
+	Done() bool
+	// ScoreKey is the board the game's scores are going on, for the
+	// leaderboard to open on once the player has left.
+	ScoreKey() string
+}
+
+// Session is the visitor a game is started for.
+type Session struct {
+	Renderer *lipgloss.Renderer
+	Width    int
+	Height   int
+	// Player is nil for anonymous visitors, whose scores aren't kept.
+	Player *scores.Player
+	// Name is what other players and spectators see the visitor as.
+	Name    string
+	Seat    *Seat
+	Content *content.Content
+}
+
+// Info is a game on the Games menu.
+type Info struct {
+	Name string
+	Icon string
+	Desc string
+	// New starts a game for s.
+	New func(s Session) Game
+	// Attract, if set, is a demo of the game playing itself for when the
+	// menu is left on it.
+	Attract func(s Session) Game
+	// Boards lists the game's leaderboards.
+	Boards func(store *scores.Store, now time.Time) []Board
+}
+
+var registry []Info
+
+// Register puts games on the Games menu, after those already there.
+func Register(games ...Info) {
+	registry = append(registry, games...)
+}
+
+// Games lists the games on the Games menu, in order.
+func Games() []Info {
+	return registry
+}
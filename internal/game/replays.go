@@ -0,0 +1,306 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/koossaayy/ssh-portal/internal/scores"
+	"github.com/koossaayy/ssh-portal/internal/snake"
+)
+
+// replayListSize is how many recent replays the list shows.
+const replayListSize = 20
+
+// controlsH is the lines of playback controls under the board.
+const controlsH = 5
+
+// replaySpeeds are the playback speeds, cycled with tab.
+var replaySpeeds = []int{1, 2, 4}
+
+var errNoReplays = errors.New("replays aren't kept on this portal")
+
+// SaveReplay stores a finished game and returns its ID.
+func (s *Seat) SaveReplay(rec scores.Recording) (string, error) {
+	if s.hub.replays == nil {
+		return "", errNoReplays
+	}
+	return s.hub.replays.SaveRecording(rec)
+}
+
+// WatchCommand is what another visitor runs to watch replay id.
+func (s *Seat) WatchCommand(id string) string {
+	return "ssh -t " + strings.TrimPrefix(s.hub.sshCommand, "ssh ") + " replay " + id
+}
+
+func (s *Seat) recording(id string) (scores.Recording, error) {
+	if s.hub.replays == nil {
+		return scores.Recording{}, errNoReplays
+	}
+	return s.hub.replays.Recording(id)
+}
+
+func (s *Seat) recordings() ([]scores.Recording, error) {
+	if s.hub.replays == nil {
+		return nil, errNoReplays
+	}
+	return s.hub.replays.Recordings(Name, replayListSize)
+}
+
+// replayTickMsg advances playback; gen drops ticks scheduled before the
+// last pause or speed change.
+type replayTickMsg struct{ gen int }
+
+// Replays lists recently saved Snake games and plays one back, at 1x, 2x
+// or 4x, with pause and scrubbing.
+type Replays struct {
+	renderer *lipgloss.Renderer
+	width    int
+	height   int
+	seat     *Seat
+	list     []scores.Recording
+	cursor   int
+	notice   string
+
+	watching bool
+	rec      scores.Recording
+	replay   snake.Replay
+	// timeline is when each tick happens in the game.
+	timeline []time.Duration
+	game     *snake.Game
+	tick     int
+	speed    int
+	paused   bool
+	gen      int
+}
+
+func NewReplays(r *lipgloss.Renderer, w, h int, seat *Seat) Replays {
+	p := Replays{renderer: r, width: w, height: h, seat: seat}
+	list, err := seat.recordings()
+	if err != nil {
+		p.notice = err.Error()
+	}
+	p.list = list
+	return p
+}
+
+// Open starts playing replay id straight away, or lists the replays with
+// a notice when it can't be played.
+func (p Replays) Open(id string) (Replays, tea.Cmd) {
+	rec, err := p.seat.recording(id)
+	if errors.Is(err, scores.ErrNoRecording) {
+		p.notice = fmt.Sprintf("There's no replay %q. Here are the latest ones.", id)
+		return p, nil
+	}
+	if err != nil {
+		p.notice = err.Error()
+		return p, nil
+	}
+	var replay snake.Replay
+	if err := replay.UnmarshalBinary(rec.Data); err != nil {
+		p.notice = fmt.Sprintf("Replay %s can't be played: %v", id, err)
+		return p, nil
+	}
+	p.notice = ""
+	p.watching = true
+	p.rec = rec
+	p.replay = replay
+	p.timeline = replay.Timeline()
+	p.speed = 0
+	p.paused = false
+	p.seek(0)
+	return p, p.schedule()
+}
+
+// Watching reports whether a replay is on screen rather than the list.
+func (p Replays) Watching() bool {
+	return p.watching
+}
+
+// Back stops playback and returns to the list.
+func (p Replays) Back() Replays {
+	p.watching = false
+	p.gen++
+	return p
+}
+
+func (p *Replays) seek(tick int) {
+	p.tick = min(max(tick, 0), p.replay.Len())
+	p.game = p.replay.At(p.tick)
+}
+
+// schedule asks for the next playback tick at the current speed.
+func (p *Replays) schedule() tea.Cmd {
+	p.gen++
+	if p.paused || p.tick >= p.replay.Len() {
+		return nil
+	}
+	gen := p.gen
+	interval := p.timeline[p.tick+1] - p.timeline[p.tick]
+	return tea.Tick(interval/time.Duration(replaySpeeds[p.speed]), func(time.Time) tea.Msg {
+		return replayTickMsg{gen: gen}
+	})
+}
+
+func (p Replays) Init() tea.Cmd { return nil }
+
+func (p Replays) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+
+	case replayTickMsg:
+		if !p.watching || msg.gen != p.gen {
+			return p, nil
+		}
+		p.replay.Step(p.game, p.tick)
+		p.tick++
+		return p, p.schedule()
+
+	case tea.KeyMsg:
+		if !p.watching {
+			return p.updateList(msg)
+		}
+		switch msg.String() {
+		case " ", "p":
+			p.paused = !p.paused
+			if !p.paused && p.tick >= p.replay.Len() {
+				p.seek(0)
+			}
+		case "tab":
+			p.speed = (p.speed + 1) % len(replaySpeeds)
+		case "1":
+			p.speed = 0
+		case "2":
+			p.speed = 1
+		case "4":
+			p.speed = 2
+		case "left", "h":
+			p.seek(p.tick - 25)
+		case "right", "l":
+			p.seek(p.tick + 25)
+		case "home", "0":
+			p.seek(0)
+		case "end":
+			p.seek(p.replay.Len())
+		default:
+			return p, nil
+		}
+		return p, p.schedule()
+	}
+	return p, nil
+}
+
+func (p Replays) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.list)-1 {
+			p.cursor++
+		}
+	case "enter", " ":
+		if p.cursor < len(p.list) {
+			return p.Open(p.list[p.cursor].ID)
+		}
+	}
+	return p, nil
+}
+
+func (p Replays) View() string {
+	r := p.renderer
+
+	pink   := lipgloss.Color("#FF79C6")
+	cyan   := lipgloss.Color("#8BE9FD")
+	yellow := lipgloss.Color("#F1FA8C")
+	fg     := lipgloss.Color("#F8F8F2")
+	subtle := lipgloss.Color("#6272A4")
+	purple := lipgloss.Color("#9B72CF")
+
+	footStyle := r.NewStyle().Foreground(subtle).Italic(true)
+
+	if p.watching {
+		if !fits(p.width, p.height-controlsH, p.game.W, p.game.H) {
+			return tooSmall(r, p.width, p.height, p.game.W, p.game.H+controlsH, true)
+		}
+		board := Model{
+			renderer:  r,
+			width:     p.width,
+			height:    p.height - controlsH,
+			boardW:    p.game.W,
+			boardH:    p.game.H,
+			engine:    p.game,
+			highScore: p.rec.Score,
+			watching:  p.rec.Player,
+			replay:    true,
+		}
+
+		const barWidth = 40
+		filled := 0
+		if n := p.replay.Len(); n > 0 {
+			filled = barWidth * p.tick / n
+		}
+		state := fmt.Sprintf("▶ %dx", replaySpeeds[p.speed])
+		switch {
+		case p.tick >= p.replay.Len():
+			state = "■ end"
+		case p.paused:
+			state = "⏸ paused"
+		}
+
+		var sb strings.Builder
+		sb.WriteString(board.View())
+		sb.WriteString("\n  ")
+		sb.WriteString(r.NewStyle().Foreground(yellow).Bold(true).Render(fmt.Sprintf("%-10s", state)))
+		sb.WriteString(r.NewStyle().Foreground(purple).Render(strings.Repeat("█", filled)))
+		sb.WriteString(r.NewStyle().Foreground(subtle).Render(strings.Repeat("░", barWidth-filled)))
+		sb.WriteString(r.NewStyle().Foreground(fg).Render(fmt.Sprintf("  %s / %s", playTime(p.timeline[p.tick]), playTime(p.timeline[p.replay.Len()]))))
+		sb.WriteString("\n\n")
+		sb.WriteString(footStyle.Render("  space pause  •  tab / 1 2 4 speed  •  ← → scrub  •  esc back to the list"))
+		sb.WriteString("\n")
+		sb.WriteString(footStyle.Render("  Share it: " + p.seat.WatchCommand(p.rec.ID)))
+		return sb.String()
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  📼 Snake replays"))
+	sb.WriteString("\n\n")
+	if p.notice != "" {
+		sb.WriteString(r.NewStyle().Foreground(subtle).Render("  " + p.notice))
+		sb.WriteString("\n\n")
+	}
+	if len(p.list) == 0 {
+		sb.WriteString(r.NewStyle().Foreground(subtle).Italic(true).Render("  No replays yet. Press s after a game of Snake to save one."))
+		sb.WriteString("\n")
+	}
+	for i, rec := range p.list {
+		line := fmt.Sprintf("%s  %-18s %s  %s  %s",
+			r.NewStyle().Foreground(cyan).Render(rec.ID),
+			rec.Player,
+			r.NewStyle().Foreground(yellow).Bold(true).Render(fmt.Sprintf("%4d", rec.Score)),
+			r.NewStyle().Foreground(fg).Render(fmt.Sprintf("%6s", playTime(rec.Duration))),
+			r.NewStyle().Foreground(subtle).Render(rec.At.Format("2006-01-02 15:04")),
+		)
+		if i == p.cursor {
+			sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  ▸ ") + line)
+		} else {
+			sb.WriteString("    " + line)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(footStyle.Render("  ↑↓ / j k to move  •  enter to watch  •  esc to go back"))
+	return sb.String()
+}
+
+// playTime formats d as minutes and seconds, e.g. "1:05".
+func playTime(d time.Duration) string {
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -3,181 +3,664 @@ package game
 import (
 	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/koossaayy/ssh-portal/internal/content"
+	"github.com/koossaayy/ssh-portal/internal/scores"
+	"github.com/koossaayy/ssh-portal/internal/snake"
 )
 
-type point struct{ x, y int }
+// Name is the game's key in the score store.
+const Name = "snake"
 
-type direction int
+// ScoreKey is where scores in mode are kept, so each mode has its own
+// leaderboard. Normal games with walls keep the key from before there were
+// modes.
+func ScoreKey(mode snake.Mode) string {
+	if mode == (snake.Mode{}) {
+		return Name
+	}
+	key := Name + "-" + strings.ToLower(mode.Difficulty.String())
+	switch {
+	case mode.Levels:
+		key += "-levels"
+	case mode.Wrap:
+		key += "-wrap"
+	}
+	return key
+}
 
-const (
-	dirUp direction = iota
-	dirDown
-	dirLeft
-	dirRight
-)
+// Boards lists a leaderboard for every mode.
+func Boards() []Board {
+	var out []Board
+	for _, mode := range snake.Modes() {
+		out = append(out, Board{Name: "Snake · " + mode.String(), Key: ScoreKey(mode)})
+	}
+	return out
+}
+
+// DailyKey is where scores for the challenge of day, in UTC, are kept.
+func DailyKey(day time.Time) string {
+	return dailyPrefix + day.UTC().Format(time.DateOnly)
+}
 
-type tickMsg time.Time
+const dailyPrefix = Name + "-daily-"
 
-func tick() tea.Cmd {
-	return tea.Tick(120*time.Millisecond, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+// dailyDays is how many days of daily challenges the leaderboard goes
+// back.
+const dailyDays = 30
+
+// DailyBoards lists a leaderboard for today's challenge and for each past
+// day someone played, newest first.
+func DailyBoards(store *scores.Store, now time.Time) []Board {
+	keys, _ := store.Games(dailyPrefix)
+	if today := DailyKey(now); !slices.Contains(keys, today) {
+		keys = append(keys, today)
+	}
+	slices.Sort(keys)
+	slices.Reverse(keys)
+	var out []Board
+	for _, key := range keys[:min(len(keys), dailyDays)] {
+		out = append(out, Board{Name: "Snake daily · " + strings.TrimPrefix(key, dailyPrefix), Key: key, Day: true})
+	}
+	return out
+}
+
+// Snake is Snake, in the mode of the player's choosing.
+var Snake = Info{
+	Name: "Snake",
+	Icon: "🐍",
+	Desc: "Eat, grow, don't bite yourself",
+	New: func(s Session) Game {
+		return newFor(s).Restart()
+	},
+	Attract: func(s Session) Game {
+		return Attract(s.Renderer, s.Width, s.Height)
+	},
+	Boards: func(*scores.Store, time.Time) []Board {
+		return Boards()
+	},
+}
+
+// Daily is the day's Snake challenge.
+var Daily = Info{
+	Name: "Daily Challenge",
+	Icon: "📅",
+	Desc: "Today's Snake board, the same for everyone",
+	New: func(s Session) Game {
+		return newFor(s).Daily()
+	},
+	Boards: DailyBoards,
 }
 
-type gameState int
+// newFor sets up Snake for s's visitor.
+func newFor(s Session) Model {
+	m := New(s.Renderer, s.Width, s.Height, s.Player, s.Seat)
+	m.SetName(s.Name)
+	m.SetLevels(s.Content.Levels)
+	return m
+}
 
+// The board follows the terminal between these bounds. sideW is what
+// sits beside the board: the margin, its border and the stats panel.
+// compactH and roomyH are the lines above and below it when space is
+// short and when there's plenty; the stats panel needs statsH whatever
+// the board.
 const (
-	statePlaying gameState = iota
-	stateGameOver
+	minBoardW = 30
+	minBoardH = 12
+	maxBoardW = 60
+	maxBoardH = 25
+	sideW     = 28
+	compactH  = 8
+	roomyH    = 13
+	statsH    = 20
 )
 
+// fitBoard picks the biggest board a w×h terminal shows in full, and
+// whether it's at least the smallest one playable.
+func fitBoard(w, h int) (bw, bh int, ok bool) {
+	bw = min(w-sideW, maxBoardW)
+	bh = min(h-compactH, maxBoardH)
+	ok = bw >= minBoardW && bh >= minBoardH
+	return max(bw, minBoardW), max(bh, minBoardH), ok
+}
+
+// fits reports whether a bw×bh board can be drawn on a w×h terminal.
+func fits(w, h, bw, bh int) bool {
+	return w >= bw+sideW && h >= max(bh+compactH, statsH)
+}
+
+// attractPause is how long the demo shows its game over before going
+// again.
+const attractPause = 2 * time.Second
+
+// tickMsg steps engine; gen drops ticks scheduled before a pause. Ticks
+// name their engine since a session can have two games going, its own and
+// the demo on the menu.
+type tickMsg struct {
+	engine *snake.Game
+	gen    int
+}
+
+func (m Model) tick() tea.Cmd {
+	return m.tickAfter(m.engine.Mode.Interval(m.engine.Score))
+}
+
+func (m Model) tickAfter(d time.Duration) tea.Cmd {
+	engine, gen := m.engine, m.gen
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return tickMsg{engine: engine, gen: gen}
+	})
+}
+
+// Controller steers the snake in place of the keys: Next is asked for the
+// turn to make on every tick. g must not be changed.
+type Controller interface {
+	Next(g *snake.Game) snake.Dir
+}
+
+// Model plays Snake in a terminal: it turns keys into the engine's inputs,
+// steps it on every tick and draws it. The rules live in package snake.
 type Model struct {
 	renderer  *lipgloss.Renderer
 	width     int
 	height    int
 	boardW    int
 	boardH    int
-	snake     []point
-	dir       direction
-	nextDir   direction
-	food      point
-	score     int
+	engine    *snake.Game
+	// rng seeds each round, and rec records it for replaying.
+	rng       *rand.Rand
+	rec       snake.Replay
+	// pending is the turn to make on the next tick.
+	pending   snake.Dir
+	// mode is what the next round is played by; choosing shows the picker
+	// for it. levels are what a round of levels is played on.
+	mode      snake.Mode
+	choosing  bool
+	levels    []*snake.Level
+	// daily plays the day's challenge instead of mode; day is the one the
+	// round is on.
+	daily     bool
+	day       time.Time
+	dailyErr  string
+	// pilot steers instead of the keys when set. A round it has had a
+	// hand in is botted, and isn't scored.
+	pilot     Controller
+	botted    bool
+	// attract is the demo of the bot playing, round after round.
+	attract   bool
+	// paused stops the ticks, at the player's request or because the
+	// window changed size, which pauseNote says.
+	paused    bool
+	pauseNote string
+	gen       int
 	highScore int
-	state     gameState
-	Quit      bool
+	quit      bool
+
+	// player is nil for anonymous visitors, whose scores aren't kept.
+	player  *scores.Player
+	// bests are the player's bests so far by score key, so picking a mode
+	// doesn't go back to the store.
+	bests   map[string]int
+	newBest bool
+	saveErr string
+	naming  bool
+	name    nameInput
+	// replayID is set once the round's replay is saved.
+	replayID  string
+	replayErr string
+
+	// live broadcasts the game to spectators under player's name.
+	live       *Seat
+	playerName string
+	// watching is the name of the player whose game this is, when it's
+	// being spectated or replayed rather than played.
+	watching string
+	replay   bool
+}
+
+func New(r *lipgloss.Renderer, w, h int, player *scores.Player, live *Seat) Model {
+	bw, bh, _ := fitBoard(w, h)
+	m := Model{
+		renderer:  r,
+		width:     w,
+		height:    h,
+		boardW:    bw,
+		boardH:    bh,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		player:    player,
+		live:      live,
+	}
+	m.reset()
+	return m
+}
+
+// Spectated draws st, someone else's game, read-only.
+func Spectated(r *lipgloss.Renderer, w, h int, st SoloState) Model {
+	return Model{
+		renderer:  r,
+		width:     w,
+		height:    h,
+		boardW:    st.Game.W,
+		boardH:    st.Game.H,
+		engine:    snake.Restore(st.Game, nil),
+		highScore: st.High,
+		watching:  st.Player,
+	}
 }
 
-func New(r *lipgloss.Renderer, w, h int) Model {
+// Attract is a demo for an idle menu: the bot playing round after round.
+func Attract(r *lipgloss.Renderer, w, h int) Model {
+	bw, bh, _ := fitBoard(w, h)
 	m := Model{
 		renderer: r,
 		width:    w,
 		height:   h,
-		boardW:   60,
-		boardH:   25,
-		dir:      dirRight,
-		nextDir:  dirRight,
-		state:    statePlaying,
+		boardW:   bw,
+		boardH:   bh,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		pilot:    snake.Bot{},
+		attract:  true,
 	}
 	m.reset()
 	return m
 }
 
-func (m *Model) reset() {
-	cx, cy := m.boardW/2, m.boardH/2
-	m.snake = []point{
-		{cx, cy},
-		{cx - 1, cy},
-		{cx - 2, cy},
+// Restart sets up a fresh round, starting with the choice of mode.
+func (m Model) Restart() Model {
+	m.daily = false
+	m.reset()
+	m.choosing = true
+	return m
+}
+
+// Daily sets up a round of today's challenge.
+func (m Model) Daily() Model {
+	m.daily = true
+	m.reset()
+	m.choosing = true
+	return m
+}
+
+// ScoreKey is where the current round's scores are kept.
+func (m Model) ScoreKey() string {
+	if m.daily {
+		return DailyKey(m.day)
+	}
+	return ScoreKey(m.mode)
+}
+
+// start begins a round in the chosen mode.
+func (m *Model) start() tea.Cmd {
+	m.choosing = false
+	m.reset()
+	m.broadcast()
+	return m.tick()
+}
+
+// setMode picks the mode for the next round.
+func (m *Model) setMode(mode snake.Mode) {
+	m.mode = mode
+	m.reset()
+}
+
+func (m *Model) pause(note string) {
+	m.paused = true
+	m.pauseNote = note
+	m.gen++
+}
+
+// SetLevels sets the levels the next round of levels is played on. With
+// none, levels can't be picked. A round under way keeps its own.
+func (m *Model) SetLevels(levels []*snake.Level) {
+	m.levels = levels
+	switch {
+	case m.mode.Levels && len(levels) == 0:
+		mode := m.mode
+		mode.Levels = false
+		m.setMode(mode)
+	case m.choosing:
+		m.reset()
+	}
+}
+
+// SetName sets the name spectators see the game under.
+func (m *Model) SetName(name string) {
+	m.playerName = name
+}
+
+// Stop tells spectators the player has left the game.
+func (m Model) Stop() {
+	if m.live != nil {
+		m.live.EndBroadcast()
 	}
-	m.dir = dirRight
-	m.nextDir = dirRight
-	m.score = 0
-	m.state = statePlaying
-	m.spawnFood()
 }
 
-func (m *Model) spawnFood() {
-	occupied := map[point]bool{}
-	for _, s := range m.snake {
-		occupied[s] = true
+func (m Model) broadcast() {
+	if m.live == nil {
+		return
 	}
-	for {
-		p := point{rand.Intn(m.boardW), rand.Intn(m.boardH)}
-		if !occupied[p] {
-			m.food = p
-			return
+	m.live.Broadcast(SoloState{
+		LiveGame: LiveGame{Player: m.playerName, Score: m.engine.Score, Over: m.engine.Over},
+		High:     m.highScore,
+		Game:     m.engine.Snapshot(),
+	})
+}
+
+// Done reports whether the player has left the game.
+func (m Model) Done() bool {
+	return m.quit
+}
+
+// leave ends the game for the player and for spectators.
+func (m *Model) leave() {
+	m.quit = true
+	m.Stop()
+}
+
+func (m *Model) reset() {
+	m.dailyErr = ""
+	switch {
+	case m.daily:
+		m.day = time.Now()
+		var err error
+		if m.rec, err = snake.Daily(m.day); err != nil {
+			// The picker shows the error over an open board and won't
+			// start a round.
+			m.dailyErr = err.Error()
+			m.rec = snake.Replay{Seed: m.rng.Int63(), W: m.boardW, H: m.boardH}
+		}
+	default:
+		m.rec = snake.Replay{Seed: m.rng.Int63(), W: m.boardW, H: m.boardH, Mode: m.mode}
+		if m.mode.Levels {
+			m.rec.Levels = m.levels
 		}
 	}
+	m.highScore = m.best(m.ScoreKey())
+	m.engine = m.rec.Start()
+	m.pending = snake.None
+	m.botted = m.pilot != nil
+	m.paused = false
+	m.pauseNote = ""
+	m.gen++
+	m.newBest = false
+	m.saveErr = ""
+	m.naming = false
+	m.replayID = ""
+	m.replayErr = ""
+}
+
+// best is the player's best at key, read from the store the first time.
+func (m *Model) best(key string) int {
+	if b, ok := m.bests[key]; ok {
+		return b
+	}
+	if m.bests == nil {
+		m.bests = map[string]int{}
+	}
+	m.bests[key] = m.player.Best(key)
+	return m.bests[key]
+}
+
+// saveReplay stores the finished round for replaying and sharing.
+func (m *Model) saveReplay() {
+	data, err := m.rec.MarshalBinary()
+	if err == nil {
+		m.replayID, err = m.live.SaveReplay(scores.Recording{
+			Game:        Name,
+			Fingerprint: m.player.Fingerprint(),
+			Player:      m.playerName,
+			Score:       m.engine.Score,
+			Ticks:       m.rec.Len(),
+			Duration:    m.rec.Timeline()[m.rec.Len()],
+			Data:        data,
+		})
+	}
+	if err != nil {
+		m.replayErr = err.Error()
+	}
+}
+
+// gameOver ends the round and saves the score. Players with a key but no
+// nickname yet are asked for one so they show up on the leaderboard.
+func (m *Model) gameOver() {
+	score := m.engine.Score
+	if score > m.highScore && !m.botted {
+		m.highScore = score
+		m.newBest = m.player != nil
+		m.bests[m.ScoreKey()] = score
+	}
+	m.broadcast()
+	// The bot's scores aren't the visitor's.
+	if score == 0 || m.botted {
+		return
+	}
+	if err := m.player.Record(m.ScoreKey(), score); err != nil {
+		m.saveErr = err.Error()
+		return
+	}
+	if m.player != nil && m.player.Nickname() == "" {
+		m.naming = true
+		m.name = nameInput{}
+	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return tick()
+	return m.tick()
+}
+
+// resize fits the board to a new terminal size. A round in progress is
+// paused and its board refitted, so the visitor can take stock before
+// carrying on. Levels keep the size they were drawn at.
+func (m *Model) resize(w, h int) {
+	if w == m.width && h == m.height {
+		return
+	}
+	m.width, m.height = w, h
+	bw, bh, ok := fitBoard(w, h)
+	if ok {
+		m.boardW, m.boardH = bw, bh
+	}
+	if m.watching != "" || m.attract {
+		return
+	}
+	if m.choosing {
+		m.reset()
+		return
+	}
+	// A finished round is only drawn again, so it's refitted but not recorded.
+	if m.engine.Over {
+		if ok && !m.fits() {
+			m.engine.Resize(bw, bh)
+		}
+		return
+	}
+	m.pause("The window changed size.")
+	if !ok || m.engine.Levels != nil || (bw == m.engine.W && bh == m.engine.H) {
+		return
+	}
+	m.engine.Resize(bw, bh)
+	m.rec.Resizes = append(m.rec.Resizes, snake.Resize{At: m.rec.Len(), W: bw, H: bh})
+	m.broadcast()
+}
+
+// fits reports whether the board can be drawn in full.
+func (m Model) fits() bool {
+	return fits(m.width, m.height, m.engine.W, m.engine.H)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case *content.Content:
+		m.SetLevels(msg.Levels)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		// The demo just starts over at the new size.
+		if m.attract {
+			return m, m.start()
+		}
+		return m, nil
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc":
-			m.Quit = true
-			return m, nil
-		case "up", "k", "w":
-			if m.dir != dirDown {
-				m.nextDir = dirUp
+		if m.naming {
+			done, cancel := m.name.update(msg)
+			if cancel || (done && m.name.save(m.player)) {
+				m.naming = false
 			}
-		case "down", "j", "s":
-			if m.dir != dirUp {
-				m.nextDir = dirDown
+			return m, nil
+		}
+		if m.choosing {
+			return m.updatePicker(msg)
+		}
+		if m.engine.Over {
+			switch msg.String() {
+			case "s":
+				if m.live != nil && m.replayID == "" && !m.botted {
+					m.saveReplay()
+				}
+				return m, nil
+			case "m":
+				if !m.daily {
+					m.choosing = true
+					m.reset()
+				}
+				return m, nil
 			}
-		case "left", "h", "a":
-			if m.dir != dirRight {
-				m.nextDir = dirLeft
+		}
+		if msg.String() == "b" && !m.engine.Over {
+			if m.pilot == nil {
+				m.pilot, m.botted = snake.Bot{}, true
+				m.pending = snake.None
+			} else {
+				m.pilot = nil
 			}
-		case "right", "l", "d":
-			if m.dir != dirLeft {
-				m.nextDir = dirRight
+			m.broadcast()
+			return m, nil
+		}
+		if d, ok := keyDirection(msg.String()); ok && !m.paused && m.pilot == nil {
+			if m.engine.Turnable(d) {
+				m.pending = d
 			}
-		case "enter", " ":
-			if m.state == stateGameOver {
-				m.reset()
-				return m, tick()
+			return m, nil
+		}
+		switch msg.String() {
+		case "q", "esc":
+			m.leave()
+			return m, nil
+		case "enter", " ", "p":
+			switch {
+			case m.engine.Over:
+				if msg.String() != "p" {
+					return m, m.start()
+				}
+			case !m.paused:
+				m.pause("")
+			case m.fits():
+				m.paused = false
+				m.gen++
+				return m, m.tick()
 			}
 		}
 
 	case tickMsg:
-		if m.state == stateGameOver {
+		if msg.engine != m.engine || msg.gen != m.gen {
 			return m, nil
 		}
-		m.dir = m.nextDir
-
-		head := m.snake[0]
-		var newHead point
-		switch m.dir {
-		case dirUp:
-			newHead = point{head.x, head.y - 1}
-		case dirDown:
-			newHead = point{head.x, head.y + 1}
-		case dirLeft:
-			newHead = point{head.x - 1, head.y}
-		case dirRight:
-			newHead = point{head.x + 1, head.y}
-		}
-
-		if newHead.x < 0 || newHead.x >= m.boardW || newHead.y < 0 || newHead.y >= m.boardH {
-			m.state = stateGameOver
-			if m.score > m.highScore {
-				m.highScore = m.score
+		if m.attract && m.engine.Over {
+			return m, m.start()
+		}
+		if m.choosing || m.engine.Over || m.paused {
+			return m, nil
+		}
+		if m.pilot != nil {
+			m.pending = m.pilot.Next(m.engine)
+		}
+		m.rec.Inputs = append(m.rec.Inputs, m.pending)
+		ev := m.engine.Step(m.pending)
+		m.pending = snake.None
+		if m.engine.Over {
+			m.gameOver()
+			if m.attract {
+				return m, m.tickAfter(attractPause)
 			}
 			return m, nil
 		}
+		m.broadcast()
+		// A breather before the next level.
+		if ev == snake.Cleared && !m.attract {
+			m.pause("Level cleared! Next up: " + m.engine.Level().Name)
+			return m, nil
+		}
+		return m, m.tick()
+	}
 
-		for _, s := range m.snake {
-			if s == newHead {
-				m.state = stateGameOver
-				if m.score > m.highScore {
-					m.highScore = m.score
-				}
-				return m, nil
+	return m, nil
+}
+
+func (m Model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The daily challenge is the same for everyone: there's nothing to pick,
+	// and nothing to play when today's board couldn't be made.
+	if m.daily {
+		switch msg.String() {
+		case "enter", " ":
+			if m.dailyErr == "" {
+				m.pilot = nil
+				return m, m.start()
+			}
+		case "b":
+			if m.dailyErr == "" {
+				m.pilot = snake.Bot{}
+				return m, m.start()
 			}
+		case "q", "esc":
+			m.leave()
 		}
-
-		ate := newHead == m.food
-		m.snake = append([]point{newHead}, m.snake...)
-		if ate {
-			m.score++
-			m.spawnFood()
-		} else {
-			m.snake = m.snake[:len(m.snake)-1]
+		return m, nil
+	}
+	mode := m.mode
+	switch msg.String() {
+	case "left", "h", "right", "l":
+		step := 1
+		if k := msg.String(); k == "left" || k == "h" {
+			step = len(snake.Difficulties) - 1
 		}
-
-		return m, tick()
+		i := slices.Index(snake.Difficulties, mode.Difficulty)
+		mode.Difficulty = snake.Difficulties[(i+step)%len(snake.Difficulties)]
+	case "tab", "down", "j", "shift+tab", "up", "k":
+		boards := []snake.Mode{{}, {Wrap: true}}
+		if len(m.levels) > 0 {
+			boards = append(boards, snake.Mode{Levels: true})
+		}
+		i := slices.IndexFunc(boards, func(b snake.Mode) bool {
+			return b.Wrap == mode.Wrap && b.Levels == mode.Levels
+		})
+		step := 1
+		if k := msg.String(); k == "shift+tab" || k == "up" || k == "k" {
+			step = len(boards) - 1
+		}
+		board := boards[(i+step)%len(boards)]
+		mode.Wrap, mode.Levels = board.Wrap, board.Levels
+	case "enter", " ":
+		m.pilot = nil
+		return m, m.start()
+	case "b":
+		m.pilot = snake.Bot{}
+		return m, m.start()
+	case "q", "esc":
+		m.leave()
+		return m, nil
+	default:
+		return m, nil
 	}
-
+	m.setMode(mode)
 	return m, nil
 }
 
@@ -193,48 +676,64 @@ func (m Model) View() string {
 	subtle := lipgloss.Color("#6272A4")
 	purple := lipgloss.Color("#9B72CF")
 
-	grid := make([][]rune, m.boardH)
+	if !m.fits() {
+		return tooSmall(r, m.width, m.height, m.engine.W, m.engine.H, m.watching != "")
+	}
+	boardW, boardH := m.engine.W, m.engine.H
+	// Roomy terminals get the original airy layout.
+	top, gap, margin := "\n", "\n\n", 0
+	if m.height >= max(boardH+roomyH, statsH+roomyH-compactH) {
+		top, gap, margin = "\n\n\n", "\n\n\n", 2
+	}
+
+	grid := make([][]rune, boardH)
 	for y := range grid {
-		grid[y] = make([]rune, m.boardW)
+		grid[y] = make([]rune, boardW)
 		for x := range grid[y] {
 			grid[y][x] = ' '
 		}
 	}
 
-	for i, s := range m.snake {
-		if s.x >= 0 && s.x < m.boardW && s.y >= 0 && s.y < m.boardH {
+	for i, s := range m.engine.Snake {
+		if s.X >= 0 && s.X < boardW && s.Y >= 0 && s.Y < boardH {
 			if i == 0 {
-				grid[s.y][s.x] = '●'
+				grid[s.Y][s.X] = '●'
 			} else {
-				grid[s.y][s.x] = '○'
+				grid[s.Y][s.X] = '○'
 			}
 		}
 	}
 
-	grid[m.food.y][m.food.x] = '❤'
+	grid[m.engine.Food.Y][m.engine.Food.X] = '❤'
 
 boardStyle := r.NewStyle().
     Border(lipgloss.RoundedBorder()).
     BorderForeground(purple).
     Padding(1, 1).  // ← change Padding(0, 1) to Padding(1, 1)
-    MarginTop(2)    // ← add this
+    MarginTop(margin)
 
+	level := m.engine.Level()
 	var boardSb strings.Builder
 	for y, row := range grid {
 		for x, cell := range row {
-			pt := point{x, y}
+			pt := snake.Point{X: x, Y: y}
+			_, portal := level.Portal(pt)
 			switch {
-			case pt == m.snake[0]:
+			case level.Wall(pt):
+				boardSb.WriteString(r.NewStyle().Foreground(purple).Render("█"))
+			case portal && cell == ' ':
+				boardSb.WriteString(r.NewStyle().Foreground(pink).Render("◎"))
+			case pt == m.engine.Snake[0]:
 				boardSb.WriteString(r.NewStyle().Foreground(green).Bold(true).Render(string(cell)))
 			case m.isSnakeBody(pt):
 				boardSb.WriteString(r.NewStyle().Foreground(cyan).Render(string(cell)))
-			case pt == m.food:
+			case pt == m.engine.Food:
 				boardSb.WriteString(r.NewStyle().Foreground(red).Render(string(cell)))
 			default:
 				boardSb.WriteString(string(cell))
 			}
 		}
-		if y < m.boardH-1 {
+		if y < boardH-1 {
 			boardSb.WriteString("\n")
 		}
 	}
@@ -245,54 +744,258 @@ boardStyle := r.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(cyan).
 		Padding(1, 2).
-	    MarginTop(2).   // ← add this
+	    MarginTop(margin).
 		Width(18)
 
+	keys := r.NewStyle().Foreground(subtle).Render("w a s d\n↑ ↓ ← →\nh j k l")
+	switch {
+	case level != nil && !m.daily && m.watching == "":
+		eaten := fmt.Sprintf(" ❤ %d", m.engine.Eaten)
+		if level.Target > 0 {
+			eaten += fmt.Sprintf("/%d", level.Target)
+		}
+		keys = r.NewStyle().Foreground(subtle).Render(fmt.Sprintf("LEVEL %d/%d", m.engine.LevelNo+1, len(m.engine.Levels))) + "\n" +
+			r.NewStyle().Foreground(cyan).Bold(true).Render(" "+level.Name) + "\n" +
+			r.NewStyle().Foreground(fg).Render(eaten)
+	case m.watching != "":
+		keys = r.NewStyle().Foreground(subtle).Render("PLAYER") + "\n" +
+			r.NewStyle().Foreground(cyan).Bold(true).Render(" "+m.watching)
+	case m.pilot != nil:
+		keys = r.NewStyle().Foreground(subtle).Render("PLAYER") + "\n" +
+			r.NewStyle().Foreground(cyan).Bold(true).Render(" 🤖 the bot")
+	}
+
+	mode := m.engine.Mode.String()
+	if m.daily {
+		mode = "📅 " + m.day.UTC().Format(time.DateOnly)
+	}
 	stats := fmt.Sprintf(
 		"%s\n%s\n\n%s\n%s\n\n%s\n%s\n\n%s",
 		r.NewStyle().Foreground(yellow).Bold(true).Render("🐍 SNAKE"),
-		r.NewStyle().Foreground(subtle).Render(""),
+		r.NewStyle().Foreground(cyan).Render(mode),
 		r.NewStyle().Foreground(subtle).Render("SCORE"),
-		r.NewStyle().Foreground(pink).Bold(true).Render(fmt.Sprintf(" %d", m.score)),
+		r.NewStyle().Foreground(pink).Bold(true).Render(fmt.Sprintf(" %d", m.engine.Score)),
 		r.NewStyle().Foreground(subtle).Render("HIGH SCORE"),
 		r.NewStyle().Foreground(yellow).Bold(true).Render(fmt.Sprintf(" %d", m.highScore)),
-		r.NewStyle().Foreground(subtle).Render("w a s d\n↑ ↓ ← →\nh j k l"),
+		keys,
 	)
 
 	statsPanel := statsStyle.Render(stats)
-	gameArea := lipgloss.JoinHorizontal(lipgloss.Top, board, "  ", statsPanel)
 
 	var sb strings.Builder
-	sb.WriteString("\n\n\n")
-sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  🎮 Snake — take a break!"))
-sb.WriteString("\n\n\n")  // ← was \n\n, add one more \n here
-sb.WriteString("  ")
-sb.WriteString(gameArea)
-	sb.WriteString("\n")
+	sb.WriteString(top)
+	title := "  🎮 Snake — take a break!"
+	switch {
+	case m.attract:
+		title = "  🤖 Snake — the bot is playing"
+	case m.daily:
+		title = "  📅 Snake — the daily challenge"
+	case m.replay:
+		title = "  📼 Replay of " + m.watching + "'s game"
+	case m.watching != "":
+		title = "  👀 Watching " + m.watching + " play Snake"
+	}
+sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render(title))
+sb.WriteString(gap)
+	header := sb.String()
+	// Replays draw their own controls below the board.
+	if m.replay {
+		return header + lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel) + "\n"
+	}
+
+	// inBoard draws a box of lines in the board's place.
+	inBoard := func(color lipgloss.Color, border lipgloss.Border, lines []string) string {
+		box := r.NewStyle().
+			Border(border).
+			BorderForeground(color).
+			Padding(1, 2).
+			Width(min(lipgloss.Width(strings.Join(lines, "\n"))+4, lipgloss.Width(board)-2)).
+			Render(strings.Join(lines, "\n"))
+		return lipgloss.Place(lipgloss.Width(board), lipgloss.Height(board), lipgloss.Center, lipgloss.Center, box)
+	}
+	footStyle := r.NewStyle().Foreground(subtle).Italic(true)
+
+	if m.choosing && m.daily && m.dailyErr != "" {
+		board = inBoard(red, lipgloss.RoundedBorder(), []string{
+			r.NewStyle().Foreground(red).Bold(true).Render("📅 No daily challenge today"),
+			"",
+			r.NewStyle().Foreground(fg).Render("Couldn't set up today's board:"),
+			r.NewStyle().Foreground(subtle).Render(m.dailyErr),
+		})
+		return header + lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel) + "\n" +
+			footStyle.Render("  esc to go back to menu")
+	}
+
+	if m.choosing && m.daily {
+		lines := []string{
+			r.NewStyle().Foreground(yellow).Bold(true).Render("📅 Daily challenge"),
+			r.NewStyle().Foreground(cyan).Render(m.day.UTC().Format("Monday, 2 January")),
+			"",
+			r.NewStyle().Foreground(fg).Render("Everyone gets the same walls"),
+			r.NewStyle().Foreground(fg).Render("and the same food today."),
+			"",
+			r.NewStyle().Foreground(subtle).Render("enter to play"),
+			r.NewStyle().Foreground(subtle).Render("b to watch the bot"),
+		}
+		board = inBoard(purple, lipgloss.RoundedBorder(), lines)
+		return header + lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel) + "\n" +
+			footStyle.Render("  esc to go back to menu")
+	}
 
-	if m.state == stateGameOver {
+	if m.choosing {
+		option := func(label string, on bool) string {
+			if on {
+				return r.NewStyle().Foreground(pink).Bold(true).Render("▸" + label)
+			}
+			return r.NewStyle().Foreground(fg).Render(" " + label)
+		}
+		var difficulties []string
+		for _, d := range snake.Difficulties {
+			difficulties = append(difficulties, option(d.String(), d == m.mode.Difficulty))
+		}
+		boards := option("Solid", !m.mode.Wrap && !m.mode.Levels) + " " + option("Wrap-around", m.mode.Wrap)
+		if len(m.levels) > 0 {
+			boards += " " + option("Levels", m.mode.Levels)
+		}
+		lines := []string{
+			r.NewStyle().Foreground(yellow).Bold(true).Render("🐍 New game"),
+			"",
+			r.NewStyle().Foreground(subtle).Render("DIFFICULTY"),
+			strings.Join(difficulties, " "),
+			"",
+			r.NewStyle().Foreground(subtle).Render("BOARD"),
+			boards,
+			"",
+			r.NewStyle().Foreground(subtle).Render("← → difficulty • tab board"),
+			r.NewStyle().Foreground(subtle).Render("enter to play"),
+			r.NewStyle().Foreground(subtle).Render("b to watch the bot"),
+		}
+		board = inBoard(purple, lipgloss.RoundedBorder(), lines)
+		return header + lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel) + "\n" +
+			footStyle.Render("  esc to go back to menu")
+	}
+
+	if m.paused && m.watching == "" {
+		lines := []string{r.NewStyle().Foreground(yellow).Bold(true).Render("⏸  PAUSED")}
+		if m.pauseNote != "" {
+			lines = append(lines, r.NewStyle().Foreground(fg).Render(m.pauseNote))
+		}
+		lines = append(lines, "", r.NewStyle().Foreground(subtle).Render("space or p to carry on"))
+		board = inBoard(yellow, lipgloss.RoundedBorder(), lines)
+		return header + lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel) + "\n" +
+			footStyle.Render("  esc to go back to menu")
+	}
+
+	if m.engine.Over {
+		heading := r.NewStyle().Foreground(red).Bold(true).Render("💀 GAME OVER  ")
+		if m.engine.Won {
+			heading = r.NewStyle().Foreground(green).Bold(true).Render("🏁 You cleared every level!")
+		}
+		lines := []string{
+			heading,
+			r.NewStyle().Foreground(fg).Render(fmt.Sprintf("Final Score: %s", r.NewStyle().Foreground(yellow).Bold(true).Render(fmt.Sprintf("%d", m.engine.Score)))),
+		}
+		switch {
+		case m.saveErr != "":
+			lines = append(lines, r.NewStyle().Foreground(red).Render("Couldn't save your score: "+m.saveErr))
+		case m.newBest:
+			lines = append(lines, r.NewStyle().Foreground(green).Bold(true).Render("🏆 New personal best!"))
+		case m.botted && !m.attract && m.watching == "":
+			lines = append(lines, r.NewStyle().Foreground(subtle).Render("🤖 The bot played, so it isn't scored."))
+		}
+		if m.player == nil && m.engine.Score > 0 && m.watching == "" && !m.botted {
+			lines = append(lines, r.NewStyle().Foreground(subtle).Render("Connect with an SSH key to keep your scores."))
+		}
+		again := r.NewStyle().Foreground(subtle).Render("enter to play again • m to change mode")
+		if m.daily {
+			again = r.NewStyle().Foreground(subtle).Render("enter to play again")
+		}
+		switch {
+		case m.attract:
+			lines = append(lines, r.NewStyle().Foreground(subtle).Render("Going again…"))
+		case m.watching != "":
+			lines = append(lines,
+				r.NewStyle().Foreground(subtle).Render("Waiting for "+m.watching+" to play again…"),
+				r.NewStyle().Foreground(subtle).Render("esc to stop watching"),
+			)
+		case m.naming:
+			lines = append(lines,
+				"",
+				r.NewStyle().Foreground(cyan).Render("Pick a nickname for the leaderboard:"),
+				m.name.view(r),
+				r.NewStyle().Foreground(subtle).Render("enter to save • esc to skip"),
+			)
+		case m.replayID != "":
+			lines = append(lines,
+				r.NewStyle().Foreground(cyan).Render("📼 Replay saved as "+m.replayID),
+				r.NewStyle().Foreground(subtle).Render(m.live.WatchCommand(m.replayID)),
+				again,
+				r.NewStyle().Foreground(subtle).Render("esc to go back"),
+			)
+		case m.replayErr != "":
+			lines = append(lines,
+				r.NewStyle().Foreground(red).Render("Couldn't save the replay: "+m.replayErr),
+				again,
+				r.NewStyle().Foreground(subtle).Render("esc to go back"),
+			)
+		case m.live != nil && !m.botted:
+			lines = append(lines, again, r.NewStyle().Foreground(subtle).Render("s to save replay • esc to go back"))
+		default:
+			lines = append(lines, again, r.NewStyle().Foreground(subtle).Render("esc to go back"))
+		}
 		overlay := r.NewStyle().
 			Border(lipgloss.DoubleBorder()).
 			BorderForeground(red).
-			Padding(1, 4).
-		    MarginTop(2).   // ← add this
-			Render(fmt.Sprintf(
-				"%s\n%s\n%s",
-				r.NewStyle().Foreground(red).Bold(true).Render("  💀 GAME OVER  "),
-				r.NewStyle().Foreground(fg).Render(fmt.Sprintf("  Final Score: %s", r.NewStyle().Foreground(yellow).Bold(true).Render(fmt.Sprintf("%d", m.score)))),
-				r.NewStyle().Foreground(subtle).Render("  enter to restart • esc to go back"),
-			))
-		sb.WriteString("\n  ")
-		sb.WriteString(overlay)
-	} else {
-		sb.WriteString(r.NewStyle().Foreground(subtle).Italic(true).Render("  esc to go back to menu"))
+			Padding(1, 6).
+		    MarginTop(margin).
+			Render(strings.Join(lines, "\n"))
+		below := header + lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel) + "\n  " + overlay
+		if lipgloss.Height(below) <= m.height {
+			return below
+		}
+		// No room below the board: the box takes the board's place.
+		board = inBoard(red, lipgloss.DoubleBorder(), lines)
+		return header + lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel)
+	}
+
+	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, "  ", board, "  ", statsPanel))
+	sb.WriteString("\n")
+	switch {
+	case m.attract:
+		sb.WriteString(footStyle.Render("  press any key"))
+	case m.watching != "":
+		sb.WriteString(footStyle.Render("  esc to stop watching"))
+	case m.pilot != nil:
+		sb.WriteString(footStyle.Render("  b to take over  •  space or p to pause  •  esc to go back to menu"))
+	default:
+		sb.WriteString(footStyle.Render("  space or p to pause  •  b for the bot  •  esc to go back to menu"))
 	}
 
 	return sb.String()
 }
 
-func (m Model) isSnakeBody(p point) bool {
-	for _, s := range m.snake[1:] {
+// tooSmall asks for a bigger window than w×h, which can't fit a bw×bh
+// board.
+func tooSmall(r *lipgloss.Renderer, w, h, bw, bh int, watching bool) string {
+	needW, needH := bw+sideW, max(bh+compactH, statsH)
+	back := "esc to go back"
+	if watching {
+		back = "esc to stop watching"
+	}
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(lipgloss.Color("#FF79C6")).Bold(true).Render("  🖥  Your terminal is too small for Snake"))
+	sb.WriteString("\n\n")
+	sb.WriteString(r.NewStyle().Foreground(lipgloss.Color("#F8F8F2")).Render(fmt.Sprintf("  It's %d×%d; the board needs at least %d×%d.", w, h, needW, needH)))
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(lipgloss.Color("#F8F8F2")).Render("  Make the window bigger to carry on."))
+	sb.WriteString("\n\n")
+	sb.WriteString(r.NewStyle().Foreground(lipgloss.Color("#6272A4")).Italic(true).Render("  " + back))
+	return sb.String()
+}
+
+func (m Model) isSnakeBody(p snake.Point) bool {
+	for _, s := range m.engine.Snake[1:] {
 		if s == p {
 			return true
 		}
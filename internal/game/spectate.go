@@ -0,0 +1,159 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Spectator lists the Snake games other sessions are playing and shows one
+// of them live. Watching is read-only.
+type Spectator struct {
+	renderer *lipgloss.Renderer
+	width    int
+	height   int
+	seat     *Seat
+	games    []LiveGame
+	cursor   int
+	watching bool
+	game     SoloState
+	notice   string
+}
+
+func NewSpectator(r *lipgloss.Renderer, w, h int, seat *Seat) Spectator {
+	return Spectator{renderer: r, width: w, height: h, seat: seat}
+}
+
+func (s Spectator) Init() tea.Cmd {
+	return s.seat.WatchGames()
+}
+
+// Watching reports whether a game is on screen rather than the list.
+func (s Spectator) Watching() bool {
+	return s.watching
+}
+
+// Back returns from a game to the list.
+func (s Spectator) Back() Spectator {
+	s.seat.StopSpectating()
+	s.watching = false
+	return s
+}
+
+// Close ends every subscription; call it when the visitor moves on.
+func (s Spectator) Close() {
+	s.seat.StopWatchingGames()
+}
+
+func (s Spectator) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.width, s.height = msg.Width, msg.Height
+
+	case liveListMsg:
+		s.games = msg.games
+		s.cursor = min(s.cursor, max(len(s.games)-1, 0))
+		if msg.updates != nil {
+			return s, nextGames(msg.updates)
+		}
+
+	case liveGameMsg:
+		if !s.watching || msg.state.ID != s.game.ID {
+			return s, nil
+		}
+		s.game = msg.state
+		if s.game.Left {
+			s = s.Back()
+			s.notice = s.game.Player + " stopped playing."
+			return s, nil
+		}
+		if msg.updates != nil {
+			return s, nextSolo(msg.updates)
+		}
+
+	case tea.KeyMsg:
+		if s.watching {
+			return s, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			if s.cursor > 0 {
+				s.cursor--
+			}
+		case "down", "j":
+			if s.cursor < len(s.games)-1 {
+				s.cursor++
+			}
+		case "enter", " ":
+			if s.cursor >= len(s.games) {
+				return s, nil
+			}
+			g := s.games[s.cursor]
+			cmd, ok := s.seat.Spectate(g.ID)
+			if !ok {
+				s.notice = g.Player + " has just stopped playing."
+				return s, nil
+			}
+			s.notice = ""
+			s.watching = true
+			s.game = SoloState{LiveGame: g}
+			return s, cmd
+		}
+	}
+	return s, nil
+}
+
+func (s Spectator) View() string {
+	if s.watching && len(s.game.Game.Snake) > 0 {
+		return Spectated(s.renderer, s.width, s.height, s.game).View()
+	}
+	if s.watching {
+		return "\n" + s.renderer.NewStyle().Foreground(lipgloss.Color("#6272A4")).Italic(true).Render("  Tuning in to "+s.game.Player+"…")
+	}
+
+	r := s.renderer
+
+	pink   := lipgloss.Color("#FF79C6")
+	cyan   := lipgloss.Color("#8BE9FD")
+	fg     := lipgloss.Color("#F8F8F2")
+	subtle := lipgloss.Color("#6272A4")
+	yellow := lipgloss.Color("#F1FA8C")
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  👀 Watch Snake games"))
+	sb.WriteString("\n\n")
+
+	if len(s.games) == 0 {
+		sb.WriteString(r.NewStyle().Foreground(subtle).Italic(true).Render("  Nobody is playing right now. This list updates live."))
+		sb.WriteString("\n")
+	}
+	for i, g := range s.games {
+		status := r.NewStyle().Foreground(cyan).Render("playing")
+		if g.Over {
+			status = r.NewStyle().Foreground(subtle).Render("game over")
+		}
+		line := fmt.Sprintf("%-18s %s  %s",
+			g.Player,
+			r.NewStyle().Foreground(yellow).Bold(true).Render(fmt.Sprintf("%4d", g.Score)),
+			status,
+		)
+		if i == s.cursor {
+			sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  ▸ ") + line)
+		} else {
+			sb.WriteString(r.NewStyle().Foreground(fg).Render("    ") + line)
+		}
+		sb.WriteString("\n")
+	}
+
+	if s.notice != "" {
+		sb.WriteString("\n")
+		sb.WriteString(r.NewStyle().Foreground(subtle).Render("  " + s.notice))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(subtle).Italic(true).Render("  ↑↓ / j k to move  •  enter to watch  •  esc to go back"))
+	return sb.String()
+}
@@ -0,0 +1,246 @@
+package hostkey
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/keygen"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Supported key types, in the names used by the config file.
+const (
+	Ed25519 = "ed25519"
+	RSA     = "rsa"
+	ECDSA   = "ecdsa"
+)
+
+// CertSuffix is appended to a private key path to find its host
+// certificate, matching what ssh-keygen -s writes.
+const CertSuffix = "-cert.pub"
+
+var keygenTypes = map[string]keygen.KeyType{
+	Ed25519: keygen.Ed25519,
+	RSA:     keygen.RSA,
+	ECDSA:   keygen.ECDSA,
+}
+
+// Key is a host key loaded from (or freshly written to) disk, together with
+// its host certificate when one sits next to it.
+type Key struct {
+	Path      string
+	Signer    gossh.Signer
+	Cert      *gossh.Certificate
+	Generated bool
+}
+
+// Type is the SSH algorithm name of the underlying key, e.g. ssh-ed25519.
+func (k Key) Type() string {
+	return k.Signer.PublicKey().Type()
+}
+
+func (k Key) Fingerprint() string {
+	return gossh.FingerprintSHA256(k.Signer.PublicKey())
+}
+
+// Valid reports whether typ is a key type Ensure knows how to generate.
+func Valid(typ string) bool {
+	_, ok := keygenTypes[typ]
+	return ok
+}
+
+// PathFor returns where a key of the given type lives, next to the ed25519
+// key at edPath, following the OpenSSH id_<type> naming.
+func PathFor(edPath, typ string) string {
+	if typ == Ed25519 {
+		return edPath
+	}
+	return filepath.Join(filepath.Dir(edPath), "id_"+typ)
+}
+
+// EnsureAll loads or generates one key per type, then loads every key in
+// extra, which must already exist. The ed25519 key is always included,
+// whatever types says. The server presents one key per algorithm, so two
+// keys of the same type are an error rather than one quietly shadowing the
+// other.
+func EnsureAll(edPath string, types, extra []string) ([]Key, error) {
+	seen := map[string]bool{}
+	byType := map[string]string{}
+	var keys []Key
+	add := func(path string, load func() (Key, error)) error {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+		k, err := load()
+		if err != nil {
+			return err
+		}
+		if other, ok := byType[k.Type()]; ok {
+			return fmt.Errorf("hostkey: %s and %s are both %s keys, and only one key per type can be served; drop one from host_keys", other, path, k.Type())
+		}
+		byType[k.Type()] = path
+		keys = append(keys, k)
+		return nil
+	}
+
+	for _, typ := range append([]string{Ed25519}, types...) {
+		path := PathFor(edPath, typ)
+		if err := add(path, func() (Key, error) { return Ensure(path, typ) }); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range extra {
+		if err := add(path, func() (Key, error) { return Load(path) }); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// Ensure loads the key at path, generating it when the file does not exist.
+// An existing file that cannot be read or parsed is an error: silently
+// replacing it would rotate the host key under every known_hosts file out
+// there.
+func Ensure(path, typ string) (Key, error) {
+	kt, ok := keygenTypes[typ]
+	if !ok {
+		return Key{}, fmt.Errorf("hostkey: unsupported key type %q", typ)
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return generate(path, typ, kt)
+	}
+	return Load(path)
+}
+
+// Load reads an existing private key and its optional certificate.
+func Load(path string) (Key, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("hostkey: %w", err)
+	}
+	if info.IsDir() {
+		return Key{}, fmt.Errorf("hostkey: %s is a directory", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("hostkey: existing key is unreadable, refusing to replace it: %w", err)
+	}
+	signer, err := gossh.ParsePrivateKey(data)
+	if err != nil {
+		return Key{}, fmt.Errorf("hostkey: %s: existing key is invalid, refusing to replace it: %w", path, err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		log.Warn("Host key is readable by other users", "path", path, "mode", fmt.Sprintf("%#o", perm))
+	}
+
+	k := Key{Path: path, Signer: signer}
+	if k.Cert, err = loadCert(path+CertSuffix, signer.PublicKey()); err != nil {
+		return Key{}, err
+	}
+	return k, nil
+}
+
+func generate(path, typ string, kt keygen.KeyType) (Key, error) {
+	kp, err := keygen.New(path, keygen.WithKeyType(kt), keygen.WithWrite())
+	if err != nil {
+		return Key{}, fmt.Errorf("hostkey: generating %s key at %s: %w", typ, path, err)
+	}
+	return Key{Path: path, Signer: kp.Signer(), Generated: true}, nil
+}
+
+func loadCert(path string, pub gossh.PublicKey) (*gossh.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("hostkey: %w", err)
+	}
+
+	parsed, _, _, _, err := gossh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("hostkey: %s: %w", path, err)
+	}
+	cert, ok := parsed.(*gossh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("hostkey: %s is a public key, not a certificate", path)
+	}
+	if cert.CertType != gossh.HostCert {
+		return nil, fmt.Errorf("hostkey: %s is a user certificate; sign it with ssh-keygen -h", path)
+	}
+	if !bytes.Equal(cert.Key.Marshal(), pub.Marshal()) {
+		return nil, fmt.Errorf("hostkey: %s was issued for a different key", path)
+	}
+	return cert, nil
+}
+
+// ServerOption installs every key as a host key of the SSH server. Keys
+// with a certificate are offered both plain and certified, so clients that
+// don't trust the CA can still connect.
+func ServerOption(keys []Key) ssh.Option {
+	return func(s *ssh.Server) error {
+		for _, k := range keys {
+			s.AddHostKey(k.Signer)
+			if k.Cert == nil {
+				continue
+			}
+			cs, err := gossh.NewCertSigner(k.Cert, k.Signer)
+			if err != nil {
+				return fmt.Errorf("hostkey: %s: %w", k.Path, err)
+			}
+			s.AddHostKey(cs)
+		}
+		return nil
+	}
+}
+
+// KnownHosts returns known_hosts lines for keys. Every CA that signed one of
+// the certificates gets an @cert-authority line scoped to the certificate's
+// principals on port; keys without a certificate get a plain line for hosts,
+// which should already be in known_hosts form ("example.com" or
+// "[example.com]:2222").
+func KnownHosts(keys []Key, hosts string, port int) []string {
+	var lines []string
+	seen := map[string]bool{}
+	for _, k := range keys {
+		var line string
+		if k.Cert != nil {
+			principals := k.Cert.ValidPrincipals
+			if len(principals) == 0 {
+				principals = []string{"*"}
+			}
+			// OpenSSH keeps hosts on any other port than 22 as [host]:port,
+			// and only matches patterns written the same way.
+			var patterns []string
+			for _, p := range principals {
+				if port != 22 {
+					p = fmt.Sprintf("[%s]:%d", p, port)
+				}
+				patterns = append(patterns, p)
+			}
+			line = fmt.Sprintf("@cert-authority %s %s", strings.Join(patterns, ","), authorizedKey(k.Cert.SignatureKey))
+		} else {
+			line = fmt.Sprintf("%s %s", hosts, authorizedKey(k.Signer.PublicKey()))
+		}
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func authorizedKey(pub gossh.PublicKey) string {
+	return strings.TrimSpace(string(gossh.MarshalAuthorizedKey(pub)))
+}
@@ -0,0 +1,127 @@
+package hostkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func newSigner(t *testing.T) gossh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// certified is a host key with a certificate from ca for principals.
+func certified(t *testing.T, ca gossh.Signer, principals ...string) Key {
+	t.Helper()
+	host := newSigner(t)
+	cert := &gossh.Certificate{
+		Key:             host.PublicKey(),
+		CertType:        gossh.HostCert,
+		ValidPrincipals: principals,
+		ValidBefore:     gossh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatal(err)
+	}
+	return Key{Signer: host, Cert: cert}
+}
+
+func TestKnownHosts(t *testing.T) {
+	ca := newSigner(t)
+	caKey := authorizedKey(ca.PublicKey())
+	plain := Key{Signer: newSigner(t)}
+	plainKey := authorizedKey(plain.Signer.PublicKey())
+
+	tests := []struct {
+		name  string
+		keys  []Key
+		hosts string
+		port  int
+		want  []string
+	}{
+		{
+			name:  "port 22",
+			keys:  []Key{certified(t, ca, "ssh.example.com", "example.com")},
+			hosts: "ssh.example.com",
+			port:  22,
+			want:  []string{"@cert-authority ssh.example.com,example.com " + caKey},
+		},
+		{
+			name:  "other port",
+			keys:  []Key{certified(t, ca, "ssh.example.com", "example.com")},
+			hosts: "[ssh.example.com]:2222",
+			port:  2222,
+			want:  []string{"@cert-authority [ssh.example.com]:2222,[example.com]:2222 " + caKey},
+		},
+		{
+			name:  "any principal",
+			keys:  []Key{certified(t, ca)},
+			hosts: "[ssh.example.com]:69",
+			port:  69,
+			want:  []string{"@cert-authority [*]:69 " + caKey},
+		},
+		{
+			name:  "plain key and one CA for two certificates",
+			keys:  []Key{plain, certified(t, ca, "ssh.example.com"), certified(t, ca, "ssh.example.com")},
+			hosts: "[ssh.example.com]:2222",
+			port:  2222,
+			want: []string{
+				"[ssh.example.com]:2222 " + plainKey,
+				"@cert-authority [ssh.example.com]:2222 " + caKey,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := KnownHosts(tt.keys, tt.hosts, tt.port)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("KnownHosts =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestEnsureAllRejectsSameType(t *testing.T) {
+	dir := t.TempDir()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := gossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra := filepath.Join(dir, "extra")
+	if err := os.WriteFile(extra, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ed := filepath.Join(dir, ".ssh", "id_ed25519")
+	_, err = EnsureAll(ed, nil, []string{extra})
+	if err == nil {
+		t.Fatal("EnsureAll served two ed25519 keys")
+	}
+	if !strings.Contains(err.Error(), ed) || !strings.Contains(err.Error(), extra) {
+		t.Errorf("error doesn't name both keys: %v", err)
+	}
+
+	keys, err := EnsureAll(ed, nil, []string{ed})
+	if err != nil || len(keys) != 1 {
+		t.Errorf("listing the ed25519 key again: %d keys, %v", len(keys), err)
+	}
+}
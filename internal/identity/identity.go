@@ -0,0 +1,78 @@
+// Package identity works out who is connected. Visitors who log in with a
+// public key are recognised by its fingerprint on every visit; everyone
+// else is anonymous.
+package identity
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+type Identity struct {
+	// User is the login name the visitor typed, which anyone can pick.
+	User string `json:"user"`
+	// Fingerprint is the SHA256 fingerprint of the visitor's key, empty
+	// for anonymous visitors. It is what scores and preferences are stored
+	// under.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	KeyType     string `json:"key_type,omitempty"`
+}
+
+// Of returns the identity behind s.
+func Of(s ssh.Session) Identity {
+	id := Identity{User: s.User()}
+	if key := s.PublicKey(); key != nil {
+		id.Fingerprint = gossh.FingerprintSHA256(key)
+		id.KeyType = key.Type()
+	}
+	return id
+}
+
+func (id Identity) Anonymous() bool {
+	return id.Fingerprint == ""
+}
+
+// Short abbreviates the fingerprint for display, e.g. "SHA256:u3Vb9kQe…".
+func (id Identity) Short() string {
+	if len(id.Fingerprint) <= 15 {
+		return id.Fingerprint
+	}
+	return id.Fingerprint[:15] + "…"
+}
+
+// Name is how to address the visitor.
+func (id Identity) Name() string {
+	if id.Anonymous() || strings.TrimSpace(id.User) == "" {
+		return "stranger"
+	}
+	return id.User
+}
+
+// ServerOptions make clients present their key without locking anyone out:
+// every key is accepted, and visitors without one pass an empty
+// keyboard-interactive round instead.
+func ServerOptions() []ssh.Option {
+	return []ssh.Option{
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
+	}
+}
+
+// Middleware logs who each session belongs to.
+func Middleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			id := Of(s)
+			if id.Anonymous() {
+				log.Info("👤 Anonymous visitor", "user", id.User, "remote", s.RemoteAddr())
+			} else {
+				log.Info("👤 Visitor", "user", id.User, "key", id.KeyType, "fingerprint", id.Fingerprint, "remote", s.RemoteAddr())
+			}
+			next(s)
+		}
+	}
+}
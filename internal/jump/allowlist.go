@@ -0,0 +1,91 @@
+package jump
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Allowlist says which visitor keys may jump to which servers. It is read
+// from an authorized_keys style file: a servers="name,name" option limits a
+// key to those Server Directory entries, a key without it may go anywhere.
+//
+//	servers="Dev Box,Backups" ssh-ed25519 AAAA... alice@laptop
+//	ssh-ed25519 AAAA... bob@desktop
+type Allowlist struct {
+	// keys maps a SHA256 fingerprint to the server names it may reach; a
+	// nil slice means every server.
+	keys map[string][]string
+}
+
+// LoadAllowlist parses path. Unknown options are errors rather than being
+// ignored, so a typo can't quietly widen someone's access.
+func LoadAllowlist(path string) (Allowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Allowlist{}, fmt.Errorf("jump: %w", err)
+	}
+
+	a := Allowlist{keys: map[string][]string{}}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pub, _, opts, _, err := gossh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return Allowlist{}, fmt.Errorf("jump: %s:%d: %w", path, n, err)
+		}
+		var names []string
+		for _, o := range opts {
+			v, ok := strings.CutPrefix(o, "servers=")
+			if !ok {
+				return Allowlist{}, fmt.Errorf("jump: %s:%d: unsupported option %q (only servers=\"...\" is understood)", path, n, o)
+			}
+			for _, name := range strings.Split(strings.Trim(v, `"`), ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+			if names == nil {
+				return Allowlist{}, fmt.Errorf("jump: %s:%d: servers=\"\" allows nothing, remove the key instead", path, n)
+			}
+		}
+		a.keys[gossh.FingerprintSHA256(pub)] = names
+	}
+	if err := sc.Err(); err != nil {
+		return Allowlist{}, fmt.Errorf("jump: %s: %w", path, err)
+	}
+	return a, nil
+}
+
+// Allowed reports whether key may jump to the server called name. Visitors
+// without a key never may.
+func (a Allowlist) Allowed(key gossh.PublicKey, name string) bool {
+	if key == nil {
+		return false
+	}
+	names, ok := a.keys[gossh.FingerprintSHA256(key)]
+	if !ok {
+		return false
+	}
+	if names == nil {
+		return true
+	}
+	for _, n := range names {
+		if n == "*" || strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len is the number of keys on the list.
+func (a Allowlist) Len() int {
+	return len(a.keys)
+}
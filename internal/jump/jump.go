@@ -0,0 +1,251 @@
+// Package jump turns the Server Directory into a bastion: a visitor picks a
+// server, the portal dials it and their terminal is proxied through until
+// they log out, after which they land back in the directory.
+package jump
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/koossaayy/ssh-portal/internal/servers"
+)
+
+// dialTimeout bounds the TCP connect and SSH handshake with a target.
+const dialTimeout = 10 * time.Second
+
+type Options struct {
+	Allowlist Allowlist
+	// Key is the portal's own client identity, offered to every target.
+	Key gossh.Signer
+	// KnownHosts is the known_hosts file targets are verified against. It
+	// is read on every jump, so entries can be added without a restart.
+	KnownHosts string
+	// ForwardAgent lets visitors who connected with ssh -A authenticate
+	// with their own keys and take their agent along to the target.
+	ForwardAgent bool
+}
+
+type Jumper struct {
+	opts Options
+}
+
+func New(opts Options) *Jumper {
+	return &Jumper{opts: opts}
+}
+
+// Middleware wraps interactive sessions so a later jump can take over their
+// input and window size. It must sit after bubbletea.Middleware in
+// wish.WithMiddleware so it runs first.
+func (j *Jumper) Middleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			pty, winch, ok := s.Pty()
+			if !ok {
+				next(s)
+				return
+			}
+			next(newTerminal(s, j, pty, winch))
+		}
+	}
+}
+
+// Connector returns the Server Directory's way out for s, or nil when s
+// didn't come through Middleware.
+func (j *Jumper) Connector(s ssh.Session) servers.Connector {
+	t, ok := s.(*terminal)
+	if !ok {
+		return nil
+	}
+	return t
+}
+
+func (t *terminal) Allowed(srv servers.Server) bool {
+	return t.jumper.opts.Allowlist.Allowed(t.PublicKey(), srv.Name)
+}
+
+func (t *terminal) Connect(srv servers.Server) tea.Cmd {
+	return tea.Exec(&hop{t: t, srv: srv}, func(err error) tea.Msg {
+		return servers.ConnectedMsg{Server: srv, Err: err}
+	})
+}
+
+// hop is one jump, run by Bubble Tea once it has let go of the terminal.
+// It talks to the session directly, so the streams tea.Exec hands it are
+// ignored.
+type hop struct {
+	t   *terminal
+	srv servers.Server
+}
+
+func (h *hop) SetStdin(io.Reader)  {}
+func (h *hop) SetStdout(io.Writer) {}
+func (h *hop) SetStderr(io.Writer) {}
+
+func (h *hop) Run() error {
+	t, opts := h.t, h.t.jumper.opts
+	// The TUI's last Read is still waiting on the session; release it
+	// before the remote shell starts reading.
+	t.in.handOver()
+	defer t.in.handOver()
+
+	user, addr, err := servers.Destination(h.srv.Host)
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		user = t.User()
+	}
+
+	var signers []gossh.Signer
+	var keyring agent.ExtendedAgent
+	if opts.ForwardAgent && ssh.AgentRequested(t.Session) {
+		ch, err := t.agentChannel()
+		if err != nil {
+			log.Warn("Could not reach the visitor's agent", "user", t.User(), "error", err)
+		} else {
+			defer ch.Close()
+			keyring = agent.NewClient(ch)
+			if signers, err = keyring.Signers(); err != nil {
+				log.Warn("Could not list the visitor's agent keys", "user", t.User(), "error", err)
+			}
+		}
+	}
+	if opts.Key != nil {
+		signers = append(signers, opts.Key)
+	}
+
+	fmt.Fprintf(t.Session, "Connecting to %s (%s@%s)…\r\n", h.srv.Name, user, addr)
+	log.Info("🪂 Jumping", "visitor", t.User(), "key", fingerprint(t.PublicKey()), "server", h.srv.Name, "addr", addr, "as", user)
+
+	client, err := gossh.Dial("tcp", addr, &gossh.ClientConfig{
+		User:            user,
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(signers...)},
+		HostKeyCallback: knownHostsCallback(opts.KnownHosts),
+		Timeout:         dialTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	closed := make(chan struct{})
+	go func() {
+		_ = client.Wait()
+		close(closed)
+	}()
+	go func() {
+		// Hang up on the target when the visitor goes away mid-jump.
+		select {
+		case <-t.Context().Done():
+			client.Close()
+		case <-closed:
+		}
+	}()
+
+	sess, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	if keyring != nil {
+		if err := agent.ForwardToAgent(client, keyring); err != nil {
+			return err
+		}
+		if err := agent.RequestAgentForwarding(sess); err != nil {
+			return err
+		}
+	}
+
+	winch, win, giveBack := t.borrow()
+	defer giveBack()
+	pty, _, _ := t.Session.Pty()
+	if err := sess.RequestPty(pty.Term, win.Height, win.Width, pty.Modes); err != nil {
+		return err
+	}
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		return err
+	}
+	sess.Stdout = t.Session
+	sess.Stderr = t.Session.Stderr()
+	if err := sess.Shell(); err != nil {
+		return err
+	}
+	go func() {
+		_, _ = io.Copy(stdin, t.in)
+		stdin.Close()
+	}()
+	go func() {
+		for {
+			select {
+			case w := <-winch:
+				_ = sess.WindowChange(w.Height, w.Width)
+			case <-closed:
+				return
+			}
+		}
+	}()
+
+	err = sess.Wait()
+	var exit *gossh.ExitError
+	var missing *gossh.ExitMissingError
+	if errors.As(err, &exit) || errors.As(err, &missing) {
+		// The remote shell's exit status is the visitor's business.
+		err = nil
+	}
+	log.Info("🪂 Jump ended", "visitor", t.User(), "server", h.srv.Name, "error", err)
+	return err
+}
+
+// agentChannel opens a channel to the visitor's forwarded agent.
+func (t *terminal) agentChannel() (gossh.Channel, error) {
+	conn, ok := t.Context().Value(ssh.ContextKeyConn).(gossh.Conn)
+	if !ok {
+		return nil, errors.New("no connection in session context")
+	}
+	ch, reqs, err := conn.OpenChannel("auth-agent@openssh.com", nil)
+	if err != nil {
+		return nil, err
+	}
+	go gossh.DiscardRequests(reqs)
+	return ch, nil
+}
+
+// knownHostsCallback verifies targets against path, explaining the two
+// failures an operator can act on: a host that was never added and one
+// whose key doesn't match.
+func knownHostsCallback(path string) gossh.HostKeyCallback {
+	return func(host string, remote net.Addr, key gossh.PublicKey) error {
+		check, err := knownhosts.New(path)
+		if err != nil {
+			return fmt.Errorf("cannot verify %s: %w", host, err)
+		}
+		err = check(host, remote, key)
+		var ke *knownhosts.KeyError
+		if errors.As(err, &ke) {
+			if len(ke.Want) == 0 {
+				return fmt.Errorf("%s is not in %s (%s %s)", host, path, key.Type(), gossh.FingerprintSHA256(key))
+			}
+			return fmt.Errorf("host key for %s doesn't match %s, refusing to connect (got %s %s)", host, path, key.Type(), gossh.FingerprintSHA256(key))
+		}
+		return err
+	}
+}
+
+func fingerprint(key gossh.PublicKey) string {
+	if key == nil {
+		return "none"
+	}
+	return gossh.FingerprintSHA256(key)
+}
@@ -0,0 +1,153 @@
+package jump
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/charmbracelet/ssh"
+)
+
+// errHandedOver ends a read whose reader has given the terminal away.
+var errHandedOver = errors.New("jump: input handed over")
+
+// input reads the session in a single goroutine so a reader can be cut
+// loose without leaving a Read behind that swallows the next keystroke.
+// Bubble Tea can't cancel a blocking read on a network session, so without
+// this the first key typed on the remote host would go to the TUI.
+type input struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	buf  []byte
+	err  error
+	gen  int
+}
+
+func newInput(r io.Reader) *input {
+	in := &input{}
+	in.cond = sync.NewCond(&in.mu)
+	go in.pump(r)
+	return in
+}
+
+func (in *input) pump(r io.Reader) {
+	b := make([]byte, 1024)
+	for {
+		n, err := r.Read(b)
+		in.mu.Lock()
+		in.buf = append(in.buf, b[:n]...)
+		in.err = err
+		in.cond.Broadcast()
+		in.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (in *input) Read(p []byte) (int, error) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	gen := in.gen
+	for len(in.buf) == 0 && in.err == nil && gen == in.gen {
+		in.cond.Wait()
+	}
+	if gen != in.gen {
+		return 0, errHandedOver
+	}
+	if len(in.buf) > 0 {
+		n := copy(p, in.buf)
+		in.buf = in.buf[n:]
+		return n, nil
+	}
+	return 0, in.err
+}
+
+// handOver makes every pending Read return without consuming anything.
+func (in *input) handOver() {
+	in.mu.Lock()
+	in.gen++
+	in.cond.Broadcast()
+	in.mu.Unlock()
+}
+
+// terminal is the session as the TUI sees it. Input and window changes are
+// routed through it so a jump can borrow them and give them back.
+type terminal struct {
+	ssh.Session
+	jumper *Jumper
+	in     *input
+
+	mu  sync.Mutex
+	win ssh.Window
+	tui chan ssh.Window
+	// hop receives window changes instead of tui while a jump is running.
+	hop chan ssh.Window
+}
+
+func newTerminal(s ssh.Session, j *Jumper, pty ssh.Pty, winch <-chan ssh.Window) *terminal {
+	t := &terminal{
+		Session: s,
+		jumper:  j,
+		in:      newInput(s),
+		win:     pty.Window,
+		tui:     make(chan ssh.Window, 1),
+	}
+	go t.route(winch)
+	return t
+}
+
+func (t *terminal) Read(p []byte) (int, error) {
+	return t.in.Read(p)
+}
+
+func (t *terminal) Pty() (ssh.Pty, <-chan ssh.Window, bool) {
+	pty, _, ok := t.Session.Pty()
+	t.mu.Lock()
+	pty.Window = t.win
+	t.mu.Unlock()
+	return pty, t.tui, ok
+}
+
+func (t *terminal) route(winch <-chan ssh.Window) {
+	for w := range winch {
+		t.mu.Lock()
+		t.win = w
+		dst := t.tui
+		if t.hop != nil {
+			dst = t.hop
+		}
+		t.mu.Unlock()
+		offer(dst, w)
+	}
+}
+
+// borrow diverts window changes to a jump until the returned func is
+// called, which hands them, and the current size, back to the TUI.
+func (t *terminal) borrow() (<-chan ssh.Window, ssh.Window, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hop = make(chan ssh.Window, 1)
+	return t.hop, t.win, func() {
+		t.mu.Lock()
+		t.hop = nil
+		w := t.win
+		t.mu.Unlock()
+		offer(t.tui, w)
+	}
+}
+
+// offer delivers w, replacing a size nobody has picked up yet.
+func offer(ch chan ssh.Window, w ssh.Window) {
+	for {
+		select {
+		case ch <- w:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
@@ -2,64 +2,22 @@ package portfolio
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
-	"runtime"
-	"os/exec"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 type Project struct {
-	Name   string
-	Desc   string
-	Tech   []string
-	URL    string
-	Status string
-	Emoji  string
-}
-
-var projects = []Project{
-	{
-		Name:   "SSH Portal",
-		Desc:   "This very SSH portal — built with Charm's wish + bubbletea stack. Because why not.",
-		Tech:   []string{"Go", "Wish", "BubbleTea", "Lipgloss"},
-		URL:    "ssh ssh.koossaayy.tn -p 69",
-		Status: "Live",
-		Emoji:  "🟢",
-	},
-	{
-		Name:   "Laralingo",
-		Desc:   "Manage your localization & translation process as code, and never miss anything.",
-		Tech:   []string{"Laravel", "React", "Inertia", "GitHub", "GitLab", "AI & Translation APIs"},
-		URL:    "laralingo.app",
-		Status: "Closed Preview",
-		Emoji:  "🔵",
-	},
-	{
-		Name:   "Personal Blog",
-		Desc:   "Well, I got to write something somewhere right?",
-		Tech:   []string{"Laravel", "Statamic"},
-		URL:    "https://koossaayy.tn",
-		Status: "Live",
-		Emoji:  "🟢",
-	},
-	{
-		Name:   "Devs.tn",
-		Desc:   "Linktree but for Tunisian devs. Because we deserve our own corner of the internet.",
-		Tech:   []string{"Laravel", "React", "Inertia"},
-		URL:    "devs.tn",
-		Status: "Ongoing",
-		Emoji:  "🟡",
-	},
-	{
-		Name:   "SUPER DUPER SECRET PROJECT",
-		Desc:   "SUPER DUPER SECRET DESCRIPTION. But lawyers gonna love it. 🤫",
-		Tech:   []string{"Laravel", "React", "Inertia"},
-		URL:    "¯\\_(ツ)_/¯",
-		Status: "Ongoing",
-		Emoji:  "🔴",
-	},
+	Name   string   `json:"name" yaml:"name"`
+	Desc   string   `json:"description" yaml:"description"`
+	Tech   []string `json:"tech" yaml:"tech"`
+	URL    string   `json:"url" yaml:"url"`
+	Status string   `json:"status" yaml:"status"`
+	Emoji  string   `json:"emoji" yaml:"emoji"`
 }
 
 // Tech badge colors — each tech gets its own vibe
@@ -87,15 +45,31 @@ var statusColors = map[string]string{
 	"Ongoing":     "#F1FA8C",
 }
 
+// toastTTL is how long the "copied" confirmation stays up.
+const toastTTL = 3 * time.Second
+
+type toastDoneMsg struct{ id int }
+
 type Model struct {
 	renderer *lipgloss.Renderer
 	width    int
 	height   int
 	cursor   int
+	projects []Project
+	toast    string
+	toastID  int
+}
+
+func New(r *lipgloss.Renderer, w, h int, projects []Project) Model {
+	return Model{renderer: r, width: w, height: h, cursor: 0, projects: projects}
 }
 
-func New(r *lipgloss.Renderer, w, h int) Model {
-	return Model{renderer: r, width: w, height: h, cursor: 0}
+// SetProjects replaces the list in place, keeping the cursor in range.
+func (m *Model) SetProjects(projects []Project) {
+	m.projects = projects
+	if m.cursor >= len(projects) {
+		m.cursor = max(len(projects)-1, 0)
+	}
 }
 
 func (m Model) Init() tea.Cmd { return nil }
@@ -108,20 +82,73 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor--
 			}
 		case "down", "j":
-			if m.cursor < len(projects)-1 {
+			if m.cursor < len(m.projects)-1 {
 				m.cursor++
 			}
-		
+
 		case "enter":
-			if m.cursor < len(projects)-1{
-				url := projects[m.cursor].URL
-				go openURL(url)
+			if m.cursor < len(m.projects) {
+				return m.copyLink(m.projects[m.cursor])
 			}
+		}
+	}
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = size.Width
+		m.height = size.Height
+	}
+	if done, ok := msg.(toastDoneMsg); ok && done.id == m.toastID {
+		m.toast = ""
 	}
-}
 	return m, nil
 }
 
+// copyLink puts the project's link on the visitor's clipboard with OSC 52.
+// The portal can't open a browser on their machine, and can't tell whether
+// their terminal honours OSC 52 either, so the toast always repeats what
+// was copied. Terminals without escape support just get the text to copy
+// by hand.
+func (m Model) copyLink(p Project) (tea.Model, tea.Cmd) {
+	target, _ := link(p.URL)
+	var cmd tea.Cmd
+	switch {
+	case target == "":
+		m.toast = "🤷 Nothing to open for " + p.Name
+	case m.renderer.ColorProfile() == termenv.Ascii:
+		m.toast = "Copy it from here: " + target
+	default:
+		out := m.renderer.Output()
+		cmd = func() tea.Msg {
+			out.Copy(target)
+			return nil
+		}
+		m.toast = "📋 Copied " + target
+	}
+	m.toastID++
+	id := m.toastID
+	return m, tea.Batch(cmd, tea.Tick(toastTTL, func(time.Time) tea.Msg { return toastDoneMsg{id} }))
+}
+
+// link returns what a project's URL field points at. Bare domains get an
+// https:// scheme and count as web links; an ssh command line is worth
+// copying but isn't a link; anything else is neither.
+func link(raw string) (target string, web bool) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "ssh ") {
+		return raw, false
+	}
+	if !strings.HasPrefix(raw, "http://") && !strings.HasPrefix(raw, "https://") {
+		if strings.ContainsAny(raw, " \\") || !strings.Contains(raw, ".") {
+			return "", false
+		}
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	return u.String(), true
+}
+
 func (m Model) View() string {
 	r := m.renderer
 
@@ -143,12 +170,12 @@ func (m Model) View() string {
 	sb.WriteString("\n")
 	sb.WriteString(titleStyle.Render("  🚀 Portfolio"))
 	sb.WriteString("  ")
-	sb.WriteString(countStyle.Render(fmt.Sprintf("(%d/%d)", m.cursor+1, len(projects))))
+	sb.WriteString(countStyle.Render(fmt.Sprintf("(%d/%d)", m.cursor+1, len(m.projects))))
 	sb.WriteString("\n")
 	sb.WriteString(r.NewStyle().Foreground(subtle).Italic(true).Render("  Things I've built, broken, and learned from."))
 	sb.WriteString("\n\n")
 
-	for i, p := range projects {
+	for i, p := range m.projects {
 		isSelected := i == m.cursor
 
 		borderColor := subtle
@@ -199,13 +226,20 @@ func (m Model) View() string {
 		}
 		techLine := strings.Join(tags, " ")
 
+		// OSC 8 makes the URL clickable where the terminal supports it;
+		// elsewhere the escape is ignored and the plain text remains.
+		urlText := r.NewStyle().Foreground(cyan).Render(p.URL)
+		if href, web := link(p.URL); web && r.ColorProfile() != termenv.Ascii {
+			urlText = r.Output().Hyperlink(href, urlText)
+		}
+
 		content := fmt.Sprintf(
 			"%s  %s\n\n%s\n\n%s  %s\n%s",
 			nameStyle.Render(p.Name),
 			statusBadge,
 			r.NewStyle().Foreground(fg).Render(p.Desc),
 			r.NewStyle().Foreground(subtle).Render("🔗"),
-			r.NewStyle().Foreground(cyan).Render(p.URL),
+			urlText,
 			techLine,
 		)
 
@@ -213,35 +247,18 @@ func (m Model) View() string {
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString("\n")
-	sb.WriteString(footStyle.Render("  ↑↓ / j k to browse  •  esc to go back"))
-	return sb.String()
-}
-
-func openURL(url string) error {
-
-	var cmd string
-	var args []string
-
-	if strings.HasPrefix(url, "ssh ") {
-		return nil
+	if m.toast != "" {
+		sb.WriteString(r.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#50FA7B")).
+			Foreground(lipgloss.Color("#50FA7B")).
+			Padding(0, 2).
+			MarginLeft(2).
+			Render(m.toast))
+		sb.WriteString("\n")
 	}
 
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		url = "https://" + url
-	}
-	switch runtime.GOOS {
-	case "windows":
-		cmd = "cmd"
-		args = []string{"/c", "start", url}
-	case "darwin":
-		cmd = "open"
-		args = []string{url}
-	case "linux":
-		cmd = "xdg-open"
-		args = []string{url}
-	default:
-		return fmt.Errorf("unsupported platform")
-	}
-	return exec.Command(cmd, args...).Start()
+	sb.WriteString("\n")
+	sb.WriteString(footStyle.Render("  ↑↓ / j k to browse  •  enter to copy link  •  esc to go back"))
+	return sb.String()
 }
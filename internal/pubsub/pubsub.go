@@ -0,0 +1,58 @@
+package pubsub
+
+import "sync"
+
+// Topic fans values out to every subscriber. Subscribers only ever care
+// about the latest value, so a slow one has its pending value replaced
+// instead of blocking the publisher.
+type Topic[T any] struct {
+	mu   sync.Mutex
+	subs map[chan T]struct{}
+}
+
+// Subscribe returns a channel receiving published values and a func that
+// unsubscribes and closes it.
+func (t *Topic[T]) Subscribe() (<-chan T, func()) {
+	ch := make(chan T, 1)
+	t.mu.Lock()
+	if t.subs == nil {
+		t.subs = map[chan T]struct{}{}
+	}
+	t.subs[ch] = struct{}{}
+	t.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.subs, ch)
+			t.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (t *Topic[T]) Publish(v T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for ch := range t.subs {
+		select {
+		case ch <- v:
+		default:
+			// Drop the stale value and retry; we hold the lock, so nobody
+			// else can refill the slot in between.
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- v
+		}
+	}
+}
+
+// Len is the number of current subscribers.
+func (t *Topic[T]) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.subs)
+}
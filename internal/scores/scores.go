@@ -0,0 +1,529 @@
+// Package scores keeps game results per visitor in a bbolt file under the
+// data directory, so high scores and saved replays survive sessions and
+// restarts.
+package scores
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/log"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/koossaayy/ssh-portal/internal/identity"
+)
+
+var (
+	playersBucket = []byte("players")
+	scoresBucket  = []byte("scores")
+	bestsBucket   = []byte("bests")
+	replaysBucket = []byte("replays")
+	talliesBucket = []byte("tallies")
+	// savedBucket lists replay IDs in the order they were saved, keyed by
+	// sequence number, so the oldest can be found without reading them all.
+	savedBucket = []byte("saved")
+)
+
+// MaxNickname is the longest nickname, in runes.
+const MaxNickname = 16
+
+// MaxRecordings is how many replays are kept; saving another drops the
+// oldest.
+const MaxRecordings = 1000
+
+// ErrNoRecording is returned for replay IDs that aren't in the store.
+var ErrNoRecording = errors.New("no such replay")
+
+type Store struct {
+	db *bolt.DB
+}
+
+// Entry is one finished game.
+type Entry struct {
+	Fingerprint string    `json:"fingerprint"`
+	Score       int       `json:"score"`
+	At          time.Time `json:"at"`
+	// Nickname is filled in when reading, so renames apply to old scores.
+	Nickname string `json:"-"`
+}
+
+// Name is what the leaderboard shows for e.
+func (e Entry) Name() string {
+	if e.Nickname != "" {
+		return e.Nickname
+	}
+	return "anon " + strings.TrimPrefix(identity.Identity{Fingerprint: e.Fingerprint}.Short(), "SHA256:")
+}
+
+type player struct {
+	Nickname string `json:"nickname"`
+}
+
+// Open opens or creates the store at path. Only one process can hold it.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("scores: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("scores: %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{playersBucket, scoresBucket, bestsBucket, replaysBucket, talliesBucket, savedBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return indexRecordings(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("scores: %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Top returns the best score of each player in game since the given time,
+// highest first, at most n of them. All-time bests are kept apart from the
+// history; shorter periods walk the history back from the newest game and
+// stop at the first one played before since. A score that can't be read is
+// logged and left off rather than losing the board.
+func (s *Store) Top(game string, since time.Time, n int) ([]Entry, error) {
+	best := map[string]Entry{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := scoresBucket
+		if since.IsZero() {
+			bucket = bestsBucket
+		}
+		b := tx.Bucket(bucket).Bucket([]byte(game))
+		if b == nil {
+			return nil
+		}
+		// Scores are keyed by sequence number, so the history runs from
+		// oldest to newest; the bests are keyed by player and all count.
+		c := b.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				log.Warn("Skipping unreadable score", "game", game, "bucket", string(bucket), "key", fmt.Sprintf("%x", k), "error", err)
+				continue
+			}
+			if e.At.Before(since) {
+				break
+			}
+			if cur, ok := best[e.Fingerprint]; !ok || e.Score > cur.Score {
+				best[e.Fingerprint] = e
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scores: %w", err)
+	}
+
+	out := make([]Entry, 0, len(best))
+	for _, e := range best {
+		out = append(out, e)
+	}
+	// Ties go to whoever got there first.
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].At.Before(out[j].At)
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	err = s.db.View(func(tx *bolt.Tx) error {
+		for i := range out {
+			out[i].Nickname = readPlayer(tx, out[i].Fingerprint).Nickname
+		}
+		return nil
+	})
+	return out, err
+}
+
+// Games lists the games with scores whose keys start with prefix, in
+// order.
+func (s *Store) Games(prefix string) ([]string, error) {
+	var out []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(scoresBucket).Cursor()
+		for k, _ := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = c.Next() {
+			out = append(out, string(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scores: %w", err)
+	}
+	return out, nil
+}
+
+// Player returns the store as seen by one visitor, or nil for anonymous
+// visitors, whose scores aren't kept. A nil Store gives a nil Player too.
+func (s *Store) Player(id identity.Identity) *Player {
+	if s == nil || id.Anonymous() {
+		return nil
+	}
+	return &Player{store: s, fingerprint: id.Fingerprint}
+}
+
+// Player reads and writes one visitor's scores. All methods are safe on a
+// nil Player and then remember nothing.
+type Player struct {
+	store       *Store
+	fingerprint string
+}
+
+func (p *Player) Fingerprint() string {
+	if p == nil {
+		return ""
+	}
+	return p.fingerprint
+}
+
+// Best is the player's all-time best in game.
+func (p *Player) Best(game string) int {
+	if p == nil {
+		return 0
+	}
+	var best Entry
+	_ = p.store.db.View(func(tx *bolt.Tx) error {
+		best, _ = readBest(tx, game, p.fingerprint)
+		return nil
+	})
+	return best.Score
+}
+
+// Record stores a finished game, and makes it the player's best if it
+// beats the one before.
+func (p *Player) Record(game string, score int) error {
+	if p == nil {
+		return nil
+	}
+	v, err := json.Marshal(Entry{Fingerprint: p.fingerprint, Score: score, At: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	err = p.store.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(scoresBucket).CreateBucketIfNotExists([]byte(game))
+		if err != nil {
+			return err
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := b.Put(binary.BigEndian.AppendUint64(nil, seq), v); err != nil {
+			return err
+		}
+		if cur, ok := readBest(tx, game, p.fingerprint); ok && cur.Score >= score {
+			return nil
+		}
+		bests, err := tx.Bucket(bestsBucket).CreateBucketIfNotExists([]byte(game))
+		if err != nil {
+			return err
+		}
+		return bests.Put([]byte(p.fingerprint), v)
+	})
+	if err != nil {
+		return fmt.Errorf("scores: %w", err)
+	}
+	return nil
+}
+
+// Tally adds counts to the player's running totals in game, such as how
+// often each key was mistyped, and returns the new totals.
+func (p *Player) Tally(game string, counts map[string]int) (map[string]int, error) {
+	if p == nil {
+		return counts, nil
+	}
+	var totals map[string]int
+	err := p.store.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(talliesBucket).CreateBucketIfNotExists([]byte(game))
+		if err != nil {
+			return err
+		}
+		totals = map[string]int{}
+		if v := b.Get([]byte(p.fingerprint)); v != nil {
+			if err := json.Unmarshal(v, &totals); err != nil {
+				return err
+			}
+		}
+		for k, n := range counts {
+			totals[k] += n
+		}
+		v, err := json.Marshal(totals)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(p.fingerprint), v)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scores: %w", err)
+	}
+	return totals, nil
+}
+
+func (p *Player) Nickname() string {
+	if p == nil {
+		return ""
+	}
+	var nick string
+	_ = p.store.db.View(func(tx *bolt.Tx) error {
+		nick = readPlayer(tx, p.fingerprint).Nickname
+		return nil
+	})
+	return nick
+}
+
+func (p *Player) SetNickname(nick string) error {
+	if p == nil {
+		return errors.New("connect with an SSH key to pick a nickname")
+	}
+	nick = strings.TrimSpace(nick)
+	if err := ValidNickname(nick); err != nil {
+		return err
+	}
+	v, err := json.Marshal(player{Nickname: nick})
+	if err != nil {
+		return err
+	}
+	return p.store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(playersBucket).Put([]byte(p.fingerprint), v)
+	})
+}
+
+// ValidNickname allows 1 to MaxNickname letters, digits, spaces and - _ .
+func ValidNickname(nick string) error {
+	n := len([]rune(nick))
+	if n == 0 || n > MaxNickname {
+		return fmt.Errorf("a nickname is 1 to %d characters", MaxNickname)
+	}
+	for _, r := range nick {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" -_.", r) {
+			return fmt.Errorf("%q can't be in a nickname", r)
+		}
+	}
+	return nil
+}
+
+func readPlayer(tx *bolt.Tx, fingerprint string) player {
+	var p player
+	if v := tx.Bucket(playersBucket).Get([]byte(fingerprint)); v != nil {
+		_ = json.Unmarshal(v, &p)
+	}
+	return p
+}
+
+// readBest is the player's best game so far in game, if they've played
+// it.
+func readBest(tx *bolt.Tx, game, fingerprint string) (Entry, bool) {
+	var e Entry
+	b := tx.Bucket(bestsBucket).Bucket([]byte(game))
+	if b == nil {
+		return e, false
+	}
+	v := b.Get([]byte(fingerprint))
+	if v == nil || json.Unmarshal(v, &e) != nil {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// Recording is a saved replay. Data is the game's own encoding; the store
+// only keeps it.
+type Recording struct {
+	ID          string        `json:"id"`
+	Game        string        `json:"game"`
+	Fingerprint string        `json:"fingerprint,omitempty"`
+	Player      string        `json:"player"`
+	Score       int           `json:"score"`
+	Ticks       int           `json:"ticks"`
+	Duration    time.Duration `json:"duration,omitempty"`
+	At          time.Time     `json:"at"`
+	Data        []byte        `json:"data"`
+}
+
+var idEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// SaveRecording stores rec under a new short ID, which it returns.
+func (s *Store) SaveRecording(rec Recording) (string, error) {
+	id := make([]byte, 5)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	rec.ID = idEncoding.EncodeToString(id)
+	rec.At = time.Now().UTC()
+	v, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(replaysBucket).Put([]byte(rec.ID), v); err != nil {
+			return err
+		}
+		seq, err := addSaved(tx, rec.ID)
+		if err != nil {
+			return err
+		}
+		if seq <= MaxRecordings {
+			return nil
+		}
+		return dropSaved(tx, seq-MaxRecordings)
+	})
+	if err != nil {
+		return "", fmt.Errorf("scores: %w", err)
+	}
+	return rec.ID, nil
+}
+
+// Recording looks up a saved replay by ID.
+func (s *Store) Recording(id string) (Recording, error) {
+	var rec Recording
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(replaysBucket).Get([]byte(strings.ToLower(strings.TrimSpace(id))))
+		if v == nil {
+			return ErrNoRecording
+		}
+		return json.Unmarshal(v, &rec)
+	})
+	return rec, err
+}
+
+// Recordings lists the newest n replays of game, without their data.
+func (s *Store) Recordings(game string, n int) ([]Recording, error) {
+	var out []Recording
+	err := s.db.View(func(tx *bolt.Tx) error {
+		replays := tx.Bucket(replaysBucket)
+		c := tx.Bucket(savedBucket).Cursor()
+		for k, id := c.Last(); k != nil && len(out) < n; k, id = c.Prev() {
+			v := replays.Get(id)
+			if v == nil {
+				continue
+			}
+			var rec Recording
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return err
+			}
+			if rec.Game == game {
+				rec.Data = nil
+				out = append(out, rec)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scores: %w", err)
+	}
+	return out, nil
+}
+
+// addSaved puts id at the end of the saved order and returns its sequence
+// number.
+func addSaved(tx *bolt.Tx, id string) (uint64, error) {
+	b := tx.Bucket(savedBucket)
+	seq, err := b.NextSequence()
+	if err != nil {
+		return 0, err
+	}
+	return seq, b.Put(binary.BigEndian.AppendUint64(nil, seq), []byte(id))
+}
+
+// dropSaved deletes the replays saved up to and including sequence number
+// last.
+func dropSaved(tx *bolt.Tx, last uint64) error {
+	replays := tx.Bucket(replaysBucket)
+	c := tx.Bucket(savedBucket).Cursor()
+	for k, id := c.First(); k != nil && binary.BigEndian.Uint64(k) <= last; k, id = c.First() {
+		if err := replays.Delete(id); err != nil {
+			return err
+		}
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexRecordings fills the saved order from the replays themselves, for
+// stores written before it was kept. It does nothing once there is one.
+func indexRecordings(tx *bolt.Tx) error {
+	saved, replays := tx.Bucket(savedBucket), tx.Bucket(replaysBucket)
+	if k, _ := saved.Cursor().First(); k != nil {
+		return nil
+	}
+	var all []Recording
+	err := replays.ForEach(func(_, v []byte) error {
+		var rec Recording
+		if err := json.Unmarshal(v, &rec); err != nil {
+			return err
+		}
+		rec.Data = nil
+		all = append(all, rec)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].At.Before(all[j].At) })
+	for _, rec := range all {
+		if _, err := addSaved(tx, rec.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Period is a leaderboard time window, in UTC.
+type Period int
+
+const (
+	AllTime Period = iota
+	ThisWeek
+	Today
+)
+
+var Periods = []Period{AllTime, ThisWeek, Today}
+
+func (p Period) String() string {
+	switch p {
+	case ThisWeek:
+		return "This week"
+	case Today:
+		return "Today"
+	default:
+		return "All-time"
+	}
+}
+
+// Since is when the period containing now started. Weeks start on Monday.
+func (p Period) Since(now time.Time) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case Today:
+		return day
+	case ThisWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Time{}
+	}
+}
@@ -0,0 +1,154 @@
+package scores
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func open(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "scores.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.db.NoSync = true
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// put appends raw values to game's history, as Record would.
+func put(t *testing.T, s *Store, game string, values ...[]byte) {
+	t.Helper()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(scoresBucket).CreateBucketIfNotExists([]byte(game))
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			seq, _ := b.NextSequence()
+			if err := b.Put(binary.BigEndian.AppendUint64(nil, seq), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func entry(t *testing.T, fp string, score int, at time.Time) []byte {
+	t.Helper()
+	v, err := json.Marshal(Entry{Fingerprint: fp, Score: score, At: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestTopSince(t *testing.T) {
+	s := open(t)
+	now := time.Now().UTC()
+	since := now.Add(-time.Hour)
+	put(t, s, "snake",
+		entry(t, "a", 50, now.Add(-2*time.Hour)),
+		entry(t, "b", 99, now.Add(-90*time.Minute)),
+		entry(t, "a", 10, now.Add(-30*time.Minute)),
+		[]byte("{not json"),
+		entry(t, "b", 7, now.Add(-20*time.Minute)),
+		entry(t, "a", 12, now.Add(-10*time.Minute)),
+	)
+
+	got, err := s.Top("snake", since, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Fingerprint != "a" || got[0].Score != 12 || got[1].Fingerprint != "b" || got[1].Score != 7 {
+		t.Errorf("Top = %+v, want a 12 then b 7", got)
+	}
+}
+
+func TestSaveRecordingDropsOldest(t *testing.T) {
+	s := open(t)
+	var ids []string
+	for i := range MaxRecordings + 3 {
+		id, err := s.SaveRecording(Recording{Game: "snake", Score: i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	for _, id := range ids[:3] {
+		if _, err := s.Recording(id); err != ErrNoRecording {
+			t.Errorf("oldest replay %s: err = %v, want it dropped", id, err)
+		}
+	}
+	if _, err := s.Recording(ids[3]); err != nil {
+		t.Errorf("replay %s: %v", ids[3], err)
+	}
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		if n := tx.Bucket(replaysBucket).Stats().KeyN; n != MaxRecordings {
+			t.Errorf("%d replays kept, want %d", n, MaxRecordings)
+		}
+		return nil
+	})
+
+	recent, err := s.Recordings("snake", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].ID != ids[len(ids)-1] || recent[1].ID != ids[len(ids)-2] {
+		t.Errorf("Recordings = %+v, want the last two saved, newest first", recent)
+	}
+}
+
+func TestOpenIndexesOldReplays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.db")
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().UTC()
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucket(replaysBucket)
+		if err != nil {
+			return err
+		}
+		// Keys sort the other way round from when the replays were saved.
+		for i := range 3 {
+			rec := Recording{ID: fmt.Sprint("r", 9-i), Game: "snake", At: start.Add(time.Duration(i) * time.Minute)}
+			v, _ := json.Marshal(rec)
+			if err := b.Put([]byte(rec.ID), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, err := s.Recordings("snake", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, rec := range got {
+		order = append(order, rec.ID)
+	}
+	if fmt.Sprint(order) != "[r7 r8 r9]" {
+		t.Errorf("Recordings = %v, want newest first [r7 r8 r9]", order)
+	}
+}
@@ -3,77 +3,100 @@ package servers
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 type Server struct {
-	Name string
-	Host string
-	Desc string
-	Icon string
-	Tag  string
+	Name string `json:"name" yaml:"name"`
+	Host string `json:"host" yaml:"host"`
+	Desc string `json:"description" yaml:"description"`
+	Icon string `json:"icon" yaml:"icon"`
+	Tag  string `json:"tag" yaml:"tag"`
 }
 
-// ── Add your servers here! ──────────────────────────────────────────────────
-var serverList = []Server{
-	{
-		Name: "This Portal",
-		Host: "ssh ssh.koossaayy.tn -p 2222",
-		Desc: "You are here. Very meta.",
-		Icon: "🌀",
-		Tag:  "portal",
-	},
-	{
-		Name: "Main Server",
-		Host: "ssh koossaayy.tn",
-		Desc: "The homelab overlord. Runs everything.",
-		Icon: "🖥️",
-		Tag:  "homelab",
-	},
-	{
-		Name: "Dev Box",
-		Host: "ssh dev.koossaayy.tn",
-		Desc: "Where code goes to be born (and sometimes die).",
-		Icon: "💻",
-		Tag:  "dev",
-	},
-	{
-		Name: "Staging",
-		Host: "ssh staging.koossaayy.tn",
-		Desc: "It works on staging, I swear.",
-		Icon: "🧪",
-		Tag:  "staging",
-	},
+// Connector hands the visitor's terminal over to one of the servers. It is
+// nil when the portal isn't configured as a jump host.
+type Connector interface {
+	// Allowed reports whether this visitor may jump to s.
+	Allowed(s Server) bool
+	// Connect proxies the session to s until either side hangs up, then
+	// delivers a ConnectedMsg.
+	Connect(s Server) tea.Cmd
+}
+
+// ConnectedMsg reports how a jump ended. Err is nil after a normal logout.
+type ConnectedMsg struct {
+	Server Server
+	Err    error
 }
 
 // ── Model ────────────────────────────────────────────────────────────────────
 
 type Model struct {
-	renderer *lipgloss.Renderer
-	width    int
-	height   int
-	cursor   int
+	renderer  *lipgloss.Renderer
+	width     int
+	height    int
+	cursor    int
+	list      []Server
+	statuses  Statuses
+	connector Connector
+	notice    string
+}
+
+func New(r *lipgloss.Renderer, w, h int, list []Server, conn Connector) Model {
+	return Model{renderer: r, width: w, height: h, list: list, connector: conn}
+}
+
+// SetList replaces the entries in place, keeping the cursor in range.
+func (m *Model) SetList(list []Server) {
+	m.list = list
+	if m.cursor >= len(list) {
+		m.cursor = max(len(list)-1, 0)
+	}
 }
 
-func New(r *lipgloss.Renderer, w, h int) Model {
-	return Model{renderer: r, width: w, height: h}
+// SetStatuses swaps in the latest probe results.
+func (m *Model) SetStatuses(st Statuses) {
+	m.statuses = st
 }
 
 func (m Model) Init() tea.Cmd { return nil }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if key, ok := msg.(tea.KeyMsg); ok {
-		switch key.String() {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case ConnectedMsg:
+		if msg.Err != nil {
+			m.notice = fmt.Sprintf("✗ %s: %v", msg.Server.Name, msg.Err)
+		} else {
+			m.notice = fmt.Sprintf("Connection to %s closed.", msg.Server.Name)
+		}
+	case tea.KeyMsg:
+		m.notice = ""
+		switch msg.String() {
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
 			}
 		case "down", "j":
-			if m.cursor < len(serverList)-1 {
+			if m.cursor < len(m.list)-1 {
 				m.cursor++
 			}
+		case "enter":
+			if m.connector == nil || m.cursor >= len(m.list) {
+				break
+			}
+			s := m.list[m.cursor]
+			if !m.connector.Allowed(s) {
+				m.notice = fmt.Sprintf("🔒 Your key may not jump to %s.", s.Name)
+				break
+			}
+			return m, m.connector.Connect(s)
 		}
 	}
 	return m, nil
@@ -101,10 +124,10 @@ func (m Model) View() string {
 
 	// Header row
 	headerStyle := r.NewStyle().Foreground(subtle).Bold(true)
-	sb.WriteString(fmt.Sprintf("  %-3s  %-18s  %-32s  %s\n", "", headerStyle.Render("NAME"), headerStyle.Render("COMMAND"), headerStyle.Render("DESCRIPTION")))
-	sb.WriteString(r.NewStyle().Foreground(subtle).Render("  " + strings.Repeat("─", m.width-6) + "\n"))
+	sb.WriteString(fmt.Sprintf("  %-3s  %-18s  %s  %-32s  %s\n", "", headerStyle.Render("NAME"), headerStyle.Render(fmt.Sprintf("%-9s", "STATUS")), headerStyle.Render("COMMAND"), headerStyle.Render("DESCRIPTION")))
+	sb.WriteString(r.NewStyle().Foreground(subtle).Render("  " + strings.Repeat("─", max(m.width-6, 0)) + "\n"))
 
-	for i, s := range serverList {
+	for i, s := range m.list {
 		isSelected := i == m.cursor
 
 		nameStyle := r.NewStyle().Foreground(fg)
@@ -122,10 +145,11 @@ func (m Model) View() string {
 			Background(purple).
 			Padding(0, 1)
 
-		line := fmt.Sprintf("%s%s  %-18s  %-32s  %s  %s",
+		line := fmt.Sprintf("%s%s  %-18s  %s  %-32s  %s  %s",
 			rowPrefix,
 			s.Icon,
 			nameStyle.Render(s.Name),
+			m.statusBadge(s.Host),
 			cmdStyle.Render(s.Host),
 			r.NewStyle().Foreground(subtle).Italic(true).Render(s.Desc),
 			tagStyle.Render(s.Tag),
@@ -134,14 +158,60 @@ func (m Model) View() string {
 		sb.WriteString("\n")
 	}
 
+	if m.cursor < len(m.list) {
+		if st, ok := m.statuses[m.list[m.cursor].Host]; ok {
+			detail := st.Banner
+			if st.Err != "" {
+				detail = st.Err
+			}
+			detail += fmt.Sprintf("  •  checked %s ago", time.Since(st.Checked).Round(time.Second))
+			sb.WriteString(r.NewStyle().Foreground(subtle).Render("  " + detail))
+			sb.WriteString("\n")
+		}
+	}
+
+	if m.notice != "" {
+		sb.WriteString(r.NewStyle().Foreground(yellow).Render("  " + m.notice))
+		sb.WriteString("\n")
+	}
+
+	tip := "Copy the command and run it in a new terminal to connect!"
+	keys := "  ↑↓ / j k to browse  •  esc to go back"
+	if m.connector != nil {
+		tip = "Press enter to hop onto the selected server through the portal."
+		keys = "  ↑↓ / j k to browse  •  enter to connect  •  esc to go back"
+		if m.cursor < len(m.list) && !m.connector.Allowed(m.list[m.cursor]) {
+			tip = "Your key isn't on the jump list for this one, copy the command instead."
+		}
+	}
 	sb.WriteString("\n")
 	sb.WriteString(r.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(purple).
 		Padding(0, 2).
-		Render(r.NewStyle().Foreground(yellow).Render("💡 Tip: ") + r.NewStyle().Foreground(fg).Render("Copy the command and run it in a new terminal to connect!")))
+		Render(r.NewStyle().Foreground(yellow).Render("💡 Tip: ") + r.NewStyle().Foreground(fg).Render(tip)))
 	sb.WriteString("\n\n")
-	sb.WriteString(footStyle.Render("  ↑↓ / j k to browse  •  esc to go back"))
+	sb.WriteString(footStyle.Render(keys))
 
 	return sb.String()
 }
+
+func (m Model) statusBadge(host string) string {
+	r := m.renderer
+	st, ok := m.statuses[host]
+	switch {
+	case !ok:
+		return r.NewStyle().Foreground(lipgloss.Color("#6272A4")).Render(fmt.Sprintf("%-9s", "◌ …"))
+	case st.Up:
+		return r.NewStyle().Foreground(lipgloss.Color("#50FA7B")).Render(fmt.Sprintf("%-9s", "● "+formatLatency(st.Latency)))
+	default:
+		return r.NewStyle().Foreground(lipgloss.Color("#FF5555")).Render(fmt.Sprintf("%-9s", "● down"))
+	}
+}
+
+func formatLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
@@ -0,0 +1,219 @@
+package servers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/koossaayy/ssh-portal/internal/pubsub"
+)
+
+// Status is the outcome of the latest probe of one server.
+type Status struct {
+	Up      bool
+	Latency time.Duration
+	// Banner is the SSH identification string, e.g. SSH-2.0-OpenSSH_9.6.
+	Banner  string
+	Err     string
+	Checked time.Time
+}
+
+// Statuses maps Server.Host to its latest Status. It is also the message
+// sessions receive whenever a probe round finishes.
+type Statuses map[string]Status
+
+// sshFlagsWithArg are the ssh(1) flags that consume the next argument, so
+// the destination isn't mistaken for one of their values.
+const sshFlagsWithArg = "BbcDEeFIiJLlmOoPpQRSWw"
+
+// Address extracts the dialable host:port from a Host command such as
+// "ssh dev.example.com", "ssh -p 2222 user@example.com" or
+// "ssh ssh://example.com:69".
+func Address(cmd string) (string, error) {
+	_, addr, err := Destination(cmd)
+	return addr, err
+}
+
+// Destination is Address plus the login name, taken from user@ (which wins,
+// as with ssh) or -l, and empty when the command leaves it to the client.
+func Destination(cmd string) (user, addr string, err error) {
+	fields := strings.Fields(cmd)
+	if len(fields) > 0 && fields[0] == "ssh" {
+		fields = fields[1:]
+	}
+
+	var host, port string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if !strings.HasPrefix(f, "-") || len(f) < 2 {
+			if host == "" {
+				host = f
+			}
+			continue
+		}
+		flag := f[1]
+		if !strings.ContainsRune(sshFlagsWithArg, rune(flag)) {
+			continue
+		}
+		val := f[2:]
+		if val == "" && i+1 < len(fields) {
+			i++
+			val = fields[i]
+		}
+		switch flag {
+		case 'p':
+			port = val
+		case 'l':
+			user = val
+		}
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("no host in %q", cmd)
+	}
+
+	if strings.HasPrefix(host, "ssh://") {
+		u, err := url.Parse(host)
+		if err != nil {
+			return "", "", fmt.Errorf("bad ssh URL %q: %w", host, err)
+		}
+		if u.User != nil {
+			user = u.User.Username()
+		}
+		host = u.Hostname()
+		if p := u.Port(); p != "" && port == "" {
+			port = p
+		}
+	}
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		user = host[:at]
+		host = host[at+1:]
+	}
+	if port == "" {
+		port = "22"
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", "", fmt.Errorf("bad port %q in %q", port, cmd)
+	}
+	return user, net.JoinHostPort(host, port), nil
+}
+
+// Probe dials addr and reads the SSH identification line. A server that
+// accepts the connection but never identifies itself is still up, but the
+// missing banner is reported in Err.
+func Probe(ctx context.Context, addr string, timeout time.Duration) Status {
+	st := Status{Checked: time.Now()}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		st.Err = err.Error()
+		return st
+	}
+	defer conn.Close()
+	st.Up = true
+	st.Latency = time.Since(start)
+
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetReadDeadline(deadline)
+	// RFC 4253 allows other lines before the identification string.
+	r := bufio.NewReaderSize(conn, 256)
+	for range 5 {
+		line, err := r.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			st.Banner = line
+			return st
+		}
+		if err != nil {
+			break
+		}
+	}
+	st.Err = "no SSH banner"
+	return st
+}
+
+// Prober probes every server on one shared schedule, however many sessions
+// are looking at the directory.
+type Prober struct {
+	interval time.Duration
+	timeout  time.Duration
+	list     func() []Server
+
+	mu      sync.RWMutex
+	latest  Statuses
+	updates pubsub.Topic[Statuses]
+}
+
+// NewProber probes the servers returned by list, which is called every
+// round so content reloads are picked up.
+func NewProber(list func() []Server, interval, timeout time.Duration) *Prober {
+	return &Prober{interval: interval, timeout: timeout, list: list, latest: Statuses{}}
+}
+
+func (p *Prober) Snapshot() Statuses {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.latest
+}
+
+func (p *Prober) Subscribe() (<-chan Statuses, func()) {
+	return p.updates.Subscribe()
+}
+
+// Run probes immediately, then every interval until ctx is done.
+func (p *Prober) Run(ctx context.Context) {
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+	for {
+		p.ProbeAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// ProbeAll runs one round over every server concurrently and publishes the
+// result.
+func (p *Prober) ProbeAll(ctx context.Context) Statuses {
+	list := p.list()
+	next := make(Statuses, len(list))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, s := range list {
+		addr, err := Address(s.Host)
+		if err != nil {
+			mu.Lock()
+			next[s.Host] = Status{Err: err.Error(), Checked: time.Now()}
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			st := Probe(ctx, addr, p.timeout)
+			mu.Lock()
+			next[s.Host] = st
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.latest = next
+	p.mu.Unlock()
+	p.updates.Publish(next)
+	return next
+}
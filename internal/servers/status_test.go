@@ -0,0 +1,172 @@
+package servers
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		user string
+		addr string
+	}{
+		{"ssh dev.example.com", "", "dev.example.com:22"},
+		{"dev.example.com", "", "dev.example.com:22"},
+		{"ssh -p 2222 user@example.com", "user", "example.com:2222"},
+		{"ssh -p2222 example.com", "", "example.com:2222"},
+		{"ssh -l bob example.com", "bob", "example.com:22"},
+		{"ssh -l bob alice@example.com", "alice", "example.com:22"},
+		{"ssh -v -i ~/.ssh/id example.com", "", "example.com:22"},
+		{"ssh -o Port=1 example.com", "", "example.com:22"},
+		{"ssh ssh://example.com:69", "", "example.com:69"},
+		{"ssh ssh://deploy@example.com", "deploy", "example.com:22"},
+		{"ssh -p 2200 ssh://example.com:69", "", "example.com:2200"},
+		{"ssh root@10.0.0.1", "root", "10.0.0.1:22"},
+	}
+	for _, tt := range tests {
+		user, addr, err := Destination(tt.cmd)
+		if err != nil {
+			t.Errorf("Destination(%q): %v", tt.cmd, err)
+			continue
+		}
+		if user != tt.user || addr != tt.addr {
+			t.Errorf("Destination(%q) = %q, %q, want %q, %q", tt.cmd, user, addr, tt.user, tt.addr)
+		}
+		if got, _ := Address(tt.cmd); got != tt.addr {
+			t.Errorf("Address(%q) = %q, want %q", tt.cmd, got, tt.addr)
+		}
+	}
+}
+
+func TestDestinationErrors(t *testing.T) {
+	for _, cmd := range []string{"", "ssh", "ssh -p 2222", "ssh -p http example.com", "ssh -p 70000 example.com"} {
+		if _, _, err := Destination(cmd); err == nil {
+			t.Errorf("Destination(%q) succeeded, want an error", cmd)
+		}
+	}
+}
+
+// serve listens on a local port and hands every connection to handle.
+func serve(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestProbeUp(t *testing.T) {
+	addr := serve(t, func(c net.Conn) {
+		c.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+	})
+	st := Probe(context.Background(), addr, time.Second)
+	if !st.Up || st.Err != "" {
+		t.Fatalf("Probe = %+v, want up", st)
+	}
+	if st.Banner != "SSH-2.0-OpenSSH_9.6" {
+		t.Errorf("Banner = %q", st.Banner)
+	}
+	if st.Checked.IsZero() {
+		t.Error("Checked not set")
+	}
+}
+
+func TestProbeBannerAfterOtherLines(t *testing.T) {
+	addr := serve(t, func(c net.Conn) {
+		c.Write([]byte("Welcome to the box\r\nBe nice\r\nSSH-2.0-dropbear\r\n"))
+	})
+	st := Probe(context.Background(), addr, time.Second)
+	if !st.Up || st.Banner != "SSH-2.0-dropbear" {
+		t.Fatalf("Probe = %+v, want the banner after the greeting", st)
+	}
+}
+
+func TestProbeNoBanner(t *testing.T) {
+	addr := serve(t, func(c net.Conn) {
+		c.Write([]byte("HTTP/1.1 400 Bad Request\r\n"))
+	})
+	st := Probe(context.Background(), addr, time.Second)
+	if !st.Up || st.Banner != "" || st.Err != "no SSH banner" {
+		t.Fatalf("Probe = %+v, want up without a banner", st)
+	}
+}
+
+func TestProbeTimeout(t *testing.T) {
+	// The server accepts but never says anything, so the probe gives up
+	// when the timeout runs out.
+	addr := serve(t, func(c net.Conn) {
+		c.Read(make([]byte, 1))
+	})
+	start := time.Now()
+	st := Probe(context.Background(), addr, 100*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Probe took %v with a 100ms timeout", elapsed)
+	}
+	if !st.Up || st.Err != "no SSH banner" {
+		t.Fatalf("Probe = %+v, want up without a banner", st)
+	}
+}
+
+func TestProbeDown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	st := Probe(context.Background(), addr, time.Second)
+	if st.Up || st.Err == "" {
+		t.Fatalf("Probe = %+v, want down with an error", st)
+	}
+}
+
+func TestProberProbeAll(t *testing.T) {
+	addr := serve(t, func(c net.Conn) {
+		c.Write([]byte("SSH-2.0-test\r\n"))
+	})
+	host, port, _ := net.SplitHostPort(addr)
+	up := "ssh -p " + port + " " + host
+	list := []Server{{Host: up}, {Host: "ssh"}}
+
+	p := NewProber(func() []Server { return list }, time.Hour, time.Second)
+	updates, unsubscribe := p.Subscribe()
+	defer unsubscribe()
+
+	got := p.ProbeAll(context.Background())
+	if st := got[up]; !st.Up || st.Banner != "SSH-2.0-test" {
+		t.Errorf("%s: %+v, want up", up, st)
+	}
+	if st := got["ssh"]; st.Up || !strings.Contains(st.Err, "no host") {
+		t.Errorf("ssh: %+v, want a parse error", st)
+	}
+	if snap := p.Snapshot(); len(snap) != 2 {
+		t.Errorf("Snapshot has %d servers, want 2", len(snap))
+	}
+	select {
+	case pub := <-updates:
+		if len(pub) != 2 {
+			t.Errorf("published %d servers, want 2", len(pub))
+		}
+	case <-time.After(time.Second):
+		t.Error("ProbeAll didn't publish")
+	}
+}
@@ -0,0 +1,149 @@
+package snake
+
+import "slices"
+
+// Bot plays Snake by itself. Every step it finds the shortest way to the
+// food with a breadth-first search and takes it if, once it has eaten, the
+// snake could still reach its own tail, so it never shuts itself in.
+// Otherwise it follows its tail, and failing that heads wherever there's
+// the most room.
+type Bot struct{}
+
+var dirs = []Dir{Up, Down, Left, Right}
+
+// Next is the turn to make on g's next step.
+func (Bot) Next(g *Game) Dir {
+	body := g.Snake
+	if path := g.search(body, g.Dir, func(p Point) bool { return p == g.Food }); path != nil {
+		if g.reachesTail(ate(body, path)) {
+			return path[0].dir
+		}
+	}
+	tail := body[len(body)-1]
+	if path := g.search(body, g.Dir, func(p Point) bool { return p == tail }); path != nil {
+		return path[0].dir
+	}
+	return g.roomiest(body)
+}
+
+// hop is one step of a path: the way to go and where it lands.
+type hop struct {
+	dir Dir
+	to  Point
+}
+
+// search finds the shortest path from body's head to a cell where goal
+// holds, heading off anywhere but back. A cell of the body is in the way
+// until the tail has moved off it, which is a step after it has gone past.
+func (st State) search(body []Point, heading Dir, goal func(Point) bool) []hop {
+	cell := func(p Point) int { return p.Y*st.W + p.X }
+	// at is 1 + the body index on each cell, or 0.
+	at := make([]int, st.W*st.H)
+	for i, p := range body {
+		at[cell(p)] = i + 1
+	}
+	type node struct {
+		p     Point
+		steps int
+	}
+	from := make([]hop, st.W*st.H)
+	seen := make([]bool, st.W*st.H)
+	seen[cell(body[0])] = true
+	queue := []node{{body[0], 0}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, d := range dirs {
+			if n.steps == 0 && d == heading.Opposite() {
+				continue
+			}
+			p, ok := st.move(n.p, d)
+			if !ok || seen[cell(p)] {
+				continue
+			}
+			if i := at[cell(p)] - 1; i >= 0 && n.steps+1 <= len(body)-i {
+				continue
+			}
+			seen[cell(p)] = true
+			from[cell(p)] = hop{dir: d, to: n.p}
+			if goal(p) {
+				var path []hop
+				for q := p; q != body[0]; q = from[cell(q)].to {
+					path = append(path, hop{dir: from[cell(q)].dir, to: q})
+				}
+				slices.Reverse(path)
+				return path
+			}
+			queue = append(queue, node{p, n.steps + 1})
+		}
+	}
+	return nil
+}
+
+// ate is body after following path and eating at its end.
+func ate(body []Point, path []hop) []Point {
+	out := make([]Point, 0, len(path)+len(body))
+	for i := len(path) - 1; i >= 0; i-- {
+		out = append(out, path[i].to)
+	}
+	out = append(out, body...)
+	return out[:min(len(out), len(body)+1)]
+}
+
+// reachesTail reports whether body's head has a way to its tail.
+func (st State) reachesTail(body []Point) bool {
+	tail := body[len(body)-1]
+	heading := None
+	if len(body) > 1 {
+		for _, d := range dirs {
+			if p, _ := st.move(body[1], d); p == body[0] {
+				heading = d
+			}
+		}
+	}
+	return st.search(body, heading, func(p Point) bool { return p == tail }) != nil
+}
+
+// roomiest is the safe turn with the most cells reachable after it, or
+// straight on when every way is blocked.
+func (g *Game) roomiest(body []Point) Dir {
+	blocked := make(map[Point]bool, len(body))
+	for _, p := range body {
+		blocked[p] = true
+	}
+	best, room := g.Dir, -1
+	for _, d := range dirs {
+		if !g.Turnable(d) {
+			continue
+		}
+		p, ok := g.move(body[0], d)
+		if !ok || blocked[p] {
+			continue
+		}
+		if n := g.fill(p, blocked); n > room {
+			best, room = d, n
+		}
+	}
+	return best
+}
+
+// fill counts the cells reachable from p without crossing blocked.
+func (st State) fill(p Point, blocked map[Point]bool) int {
+	seen := make([]bool, st.W*st.H)
+	seen[p.Y*st.W+p.X] = true
+	queue := []Point{p}
+	n := 1
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, d := range dirs {
+			q, ok := st.move(p, d)
+			if ok && !blocked[q] && !seen[q.Y*st.W+q.X] {
+				seen[q.Y*st.W+q.X] = true
+				queue = append(queue, q)
+				n++
+			}
+		}
+	}
+	return n
+}
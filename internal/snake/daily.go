@@ -0,0 +1,104 @@
+package snake
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// The daily challenge's board, sized to fit an 80×24 terminal.
+const (
+	DailyW = 40
+	DailyH = 16
+)
+
+// dailyWalls is how many wall segments the daily board gets.
+const dailyWalls = 9
+
+// Daily is the challenge for day, in UTC: everyone playing it gets the same
+// walls, the same portals and the same food, from a seed made of the date.
+// It is one level that goes on until the snake crashes, at Normal speed.
+func Daily(day time.Time) (Replay, error) {
+	date := day.UTC().Format(time.DateOnly)
+	h := fnv.New64a()
+	h.Write([]byte("snake daily " + date))
+	seed := int64(h.Sum64())
+
+	l, err := ParseLevel(dailyMap(date, rand.New(rand.NewSource(seed))))
+	if err != nil {
+		return Replay{}, fmt.Errorf("the daily board for %s doesn't parse: %w", date, err)
+	}
+	return Replay{Seed: seed, W: l.W, H: l.H, Mode: Mode{Levels: true}, Levels: []*Level{l}}, nil
+}
+
+// dailyMap lays out a board of straight wall segments and a portal pair.
+// No two segments touch, not even corner to corner, so none of them can
+// wall off part of the board; the start row is left clear.
+func dailyMap(date string, rng *rand.Rand) string {
+	grid := make([][]byte, DailyH)
+	for y := range grid {
+		grid[y] = []byte(strings.Repeat(".", DailyW))
+	}
+	start := Point{DailyW / 4, DailyH / 2}
+	reserved := func(p Point) bool {
+		return p.Y >= start.Y-1 && p.Y <= start.Y+1 && p.X >= start.X-startRoom && p.X <= start.X+startRoom*2
+	}
+	// crowded reports whether p or any cell around it is taken.
+	crowded := func(p Point) bool {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				x, y := (p.X+dx+DailyW)%DailyW, (p.Y+dy+DailyH)%DailyH
+				if grid[y][x] != '.' {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for placed, tries := 0, 0; placed < dailyWalls && tries < 1000; tries++ {
+		d := Right
+		if rng.Intn(2) == 0 {
+			d = Down
+		}
+		n := 3 + rng.Intn(6)
+		p := Point{rng.Intn(DailyW), rng.Intn(DailyH)}
+		var cells []Point
+		for range n {
+			if p.X >= DailyW || p.Y >= DailyH || reserved(p) || crowded(p) {
+				cells = nil
+				break
+			}
+			cells = append(cells, p)
+			p = d.Move(p)
+		}
+		for _, c := range cells {
+			grid[c.Y][c.X] = '#'
+		}
+		if cells != nil {
+			placed++
+		}
+	}
+	var ends []Point
+	for tries := 0; len(ends) < 2 && tries < 1000; tries++ {
+		p := Point{rng.Intn(DailyW), rng.Intn(DailyH)}
+		if !reserved(p) && !crowded(p) {
+			grid[p.Y][p.X] = 'a'
+			ends = append(ends, p)
+		}
+	}
+	if len(ends) == 1 {
+		grid[ends[0].Y][ends[0].X] = '.'
+	}
+	grid[start.Y][start.X] = '@'
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "name: Daily %s\nfood: 0\n\n", date)
+	for _, row := range grid {
+		sb.Write(row)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,173 @@
+package snake
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The biggest level there is room to draw, and the smallest worth playing.
+const (
+	MaxLevelW = 60
+	MaxLevelH = 25
+	minLevelW = 10
+	minLevelH = 5
+)
+
+// startRoom is how many free cells a level needs from its start leftwards,
+// for the longest snake any mode starts with.
+const startRoom = 5
+
+// Level is a board with obstacles: walls that end the game and portals
+// that carry the snake to their twin. Eating Target food clears it; with a
+// Target of 0 it goes on until the snake crashes. Off an open edge, the
+// snake comes back on at the opposite one.
+type Level struct {
+	Name   string
+	Target int
+	W, H   int
+	// Start is where the snake's head starts, heading right.
+	Start   Point
+	walls   map[Point]bool
+	portals map[Point]Point
+	// src is the level as written, kept for replays.
+	src string
+}
+
+// LevelError is a problem on one line of a level file.
+type LevelError struct {
+	Line int
+	Msg  string
+}
+
+func (e *LevelError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// ParseLevel reads a level file: optional "name:" and "food:" lines and a
+// blank line, then the map, where # is a wall, @ the start, a pair of the same letter
+// a portal, and a space or . the floor. Every problem found is returned,
+// each as a *LevelError.
+func ParseLevel(src string) (*Level, error) {
+	l := &Level{Target: 10, walls: map[Point]bool{}, portals: map[Point]Point{}, src: src}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var errs []error
+	fail := func(line int, format string, args ...any) {
+		errs = append(errs, &LevelError{Line: line, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	// A colon is never a map cell, so a file without one up top is all map.
+	header := strings.Contains(lines[0], ":")
+	i := 0
+	for ; header && i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+		key, value, ok := strings.Cut(lines[i], ":")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "name":
+			l.Name = value
+		case "food":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				fail(i+1, "food is how many to eat to clear the level, or 0 to play it until the snake crashes")
+			}
+			l.Target = n
+		default:
+			if !ok {
+				fail(i+1, "expected name: or food:, then a blank line before the map")
+			} else {
+				fail(i+1, "unknown setting %q", strings.TrimSpace(key))
+			}
+		}
+	}
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	first := i + 1
+	rows := lines[i:]
+	for len(rows) > 0 && strings.TrimSpace(rows[len(rows)-1]) == "" {
+		rows = rows[:len(rows)-1]
+	}
+
+	l.H = len(rows)
+	for _, row := range rows {
+		l.W = max(l.W, len([]rune(row)))
+	}
+	if l.W < minLevelW || l.H < minLevelH || l.W > MaxLevelW || l.H > MaxLevelH {
+		fail(first, "the map is %d×%d; it must be between %d×%d and %d×%d", l.W, l.H, minLevelW, minLevelH, MaxLevelW, MaxLevelH)
+		return nil, errors.Join(errs...)
+	}
+
+	starts := 0
+	l.Start = Point{l.W / 2, l.H / 2}
+	twins := map[rune][]Point{}
+	twinLine := map[rune]int{}
+	for y, row := range rows {
+		for x, c := range []rune(row) {
+			p := Point{x, y}
+			switch {
+			case c == ' ' || c == '.':
+			case c == '#':
+				l.walls[p] = true
+			case c == '@':
+				l.Start = p
+				starts++
+				if starts > 1 {
+					fail(first+y, "more than one @ start")
+				}
+			case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+				twins[c] = append(twins[c], p)
+				twinLine[c] = first + y
+			default:
+				fail(first+y, "%q isn't a map cell: use # for walls, @ for the start, letters for portals", c)
+			}
+		}
+	}
+	for _, c := range slices.Sorted(maps.Keys(twins)) {
+		ps := twins[c]
+		if len(ps) != 2 {
+			fail(twinLine[c], "portal %c needs exactly two ends, not %d", c, len(ps))
+			continue
+		}
+		l.portals[ps[0]], l.portals[ps[1]] = ps[1], ps[0]
+	}
+	for i := range startRoom {
+		p := Point{l.Start.X - i, l.Start.Y}
+		if p.X < 0 || l.blocked(p) {
+			fail(first+l.Start.Y, "the start needs %d free cells leading up to it from the left", startRoom)
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return l, nil
+}
+
+// Wall reports whether p is a wall. A nil Level has none.
+func (l *Level) Wall(p Point) bool {
+	return l != nil && l.walls[p]
+}
+
+// Portal returns where entering p takes the snake, if p is a portal.
+func (l *Level) Portal(p Point) (Point, bool) {
+	if l == nil {
+		return p, false
+	}
+	to, ok := l.portals[p]
+	return to, ok
+}
+
+// blocked reports whether food can't go on p.
+func (l *Level) blocked(p Point) bool {
+	_, portal := l.Portal(p)
+	return portal || l.Wall(p)
+}
+
+// Source is the level file the level was read from.
+func (l *Level) Source() string {
+	return l.src
+}
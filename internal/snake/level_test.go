@@ -0,0 +1,50 @@
+package snake
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const testLevel = `name: test
+food: 1
+
+##########
+#        #
+#    @   #
+#        #
+##########`
+
+func TestLevels(t *testing.T) {
+	var levels []*Level
+	for range 2 {
+		l, err := ParseLevel(testLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		levels = append(levels, l)
+	}
+
+	g := NewLevels(levels, Mode{Levels: true}, rand.New(rand.NewSource(1)))
+	if g.Head() != (Point{5, 2}) || g.W != 10 || g.H != 5 {
+		t.Fatalf("started at %v on %dx%d", g.Head(), g.W, g.H)
+	}
+	if got := g.Step(Up); got != Moved {
+		t.Fatalf("Step(Up) = %v, want Moved", got)
+	}
+	if got := g.Step(None); got != Crashed {
+		t.Fatalf("Step into the wall = %v, want Crashed", got)
+	}
+
+	g = NewLevels(levels, Mode{Levels: true}, rand.New(rand.NewSource(1)))
+	g.Food = Point{6, 2}
+	if got := g.Step(None); got != Cleared {
+		t.Fatalf("eating the last food = %v, want Cleared", got)
+	}
+	if g.LevelNo != 1 || g.Eaten != 0 || g.Score != 1 || g.Over {
+		t.Fatalf("after the first level: level %d, eaten %d, score %d, over %v", g.LevelNo, g.Eaten, g.Score, g.Over)
+	}
+	g.Food = Point{6, 2}
+	if got := g.Step(None); got != Cleared || !g.Won || !g.Over {
+		t.Fatalf("clearing the last level = %v, won %v, over %v", got, g.Won, g.Over)
+	}
+}
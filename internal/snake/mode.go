@@ -0,0 +1,80 @@
+package snake
+
+import "time"
+
+// Difficulty sets how fast a game starts and how long the snake is. The
+// zero Difficulty is Normal.
+type Difficulty int
+
+const (
+	Normal Difficulty = iota
+	Easy
+	Hard
+)
+
+var Difficulties = []Difficulty{Easy, Normal, Hard}
+
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "Easy"
+	case Hard:
+		return "Hard"
+	default:
+		return "Normal"
+	}
+}
+
+// Mode is the rules a game is played by.
+type Mode struct {
+	Difficulty Difficulty
+	// Wrap takes the snake off one edge of the board and back on at the
+	// opposite one, instead of crashing it into the wall.
+	Wrap bool
+	// Levels plays through a series of levels instead of one open board.
+	Levels bool
+}
+
+// Modes lists every mode: open boards with solid walls, then wrapping,
+// then levels, each easiest first.
+func Modes() []Mode {
+	var out []Mode
+	for _, board := range []Mode{{}, {Wrap: true}, {Levels: true}} {
+		for _, d := range Difficulties {
+			board.Difficulty = d
+			out = append(out, board)
+		}
+	}
+	return out
+}
+
+func (m Mode) String() string {
+	switch {
+	case m.Levels:
+		return m.Difficulty.String() + " · levels"
+	case m.Wrap:
+		return m.Difficulty.String() + " · wrap"
+	}
+	return m.Difficulty.String()
+}
+
+// StartLen is how long the snake is when the game starts.
+func (m Mode) StartLen() int {
+	if m.Difficulty == Hard {
+		return 5
+	}
+	return 3
+}
+
+// Interval is how long a step takes at score. The snake speeds up with
+// every point, down to a floor.
+func (m Mode) Interval(score int) time.Duration {
+	start, step, floor := TickInterval, 2*time.Millisecond, 70*time.Millisecond
+	switch m.Difficulty {
+	case Easy:
+		start, step, floor = 160*time.Millisecond, time.Millisecond, 100*time.Millisecond
+	case Hard:
+		start, floor = 90*time.Millisecond, 45*time.Millisecond
+	}
+	return max(start-time.Duration(score)*step, floor)
+}
@@ -0,0 +1,222 @@
+package snake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// replayMagic starts every encoded replay; the digit is the format version.
+const replayMagic = "SNK4"
+
+// Replay is a whole game: its mode, the seed that placed its food, the
+// input of every tick and any resizes of the board along the way. Playing
+// them back from the seed gives the same game. A game of levels keeps the
+// levels it was played on, since the files may have changed since.
+type Replay struct {
+	Seed    int64
+	W, H    int
+	Mode    Mode
+	Levels  []*Level
+	Inputs  []Dir
+	Resizes []Resize
+}
+
+// Resize is the board changing to W×H before tick At.
+type Resize struct {
+	At   int
+	W, H int
+}
+
+// Start returns the game as it was before the first tick.
+func (r Replay) Start() *Game {
+	rng := rand.New(rand.NewSource(r.Seed))
+	if r.Mode.Levels {
+		return NewLevels(r.Levels, r.Mode, rng)
+	}
+	return New(r.W, r.H, r.Mode, rng)
+}
+
+// At returns the game after the first tick ticks.
+func (r Replay) At(tick int) *Game {
+	g := r.Start()
+	for i := range min(max(tick, 0), len(r.Inputs)) {
+		r.Step(g, i)
+	}
+	return g
+}
+
+// Step plays tick i of the replay on g, which must be at tick i.
+func (r Replay) Step(g *Game, i int) Event {
+	for _, rs := range r.Resizes {
+		if rs.At == i {
+			g.Resize(rs.W, rs.H)
+		}
+	}
+	return g.Step(r.Inputs[i])
+}
+
+func (r Replay) Len() int {
+	return len(r.Inputs)
+}
+
+// Timeline gives the time into the game at which each tick starts, plus
+// when the last one ended, since the snake speeds up as it scores.
+func (r Replay) Timeline() []time.Duration {
+	g := r.Start()
+	out := make([]time.Duration, 1, len(r.Inputs)+1)
+	for i := range r.Inputs {
+		d := r.Mode.Interval(g.Score)
+		r.Step(g, i)
+		out = append(out, out[i]+d)
+	}
+	return out
+}
+
+// MarshalBinary encodes r compactly. Most ticks have no input, so inputs
+// are stored as the number of quiet ticks before each turn.
+func (r Replay) MarshalBinary() ([]byte, error) {
+	b := []byte(replayMagic)
+	b = binary.AppendVarint(b, r.Seed)
+	b = binary.AppendUvarint(b, uint64(r.W))
+	b = binary.AppendUvarint(b, uint64(r.H))
+	b = binary.AppendUvarint(b, uint64(len(r.Inputs)))
+	b = binary.AppendUvarint(b, uint64(r.Mode.Difficulty))
+	b = append(b, boolByte(r.Mode.Wrap), boolByte(r.Mode.Levels))
+	if r.Mode.Levels {
+		b = binary.AppendUvarint(b, uint64(len(r.Levels)))
+		for _, l := range r.Levels {
+			b = binary.AppendUvarint(b, uint64(len(l.src)))
+			b = append(b, l.src...)
+		}
+	}
+	b = binary.AppendUvarint(b, uint64(len(r.Resizes)))
+	for _, rs := range r.Resizes {
+		b = binary.AppendUvarint(b, uint64(rs.At))
+		b = binary.AppendUvarint(b, uint64(rs.W))
+		b = binary.AppendUvarint(b, uint64(rs.H))
+	}
+	gap := uint64(0)
+	for _, in := range r.Inputs {
+		if in == None {
+			gap++
+			continue
+		}
+		b = binary.AppendUvarint(b, gap)
+		b = append(b, byte(in))
+		gap = 0
+	}
+	return b, nil
+}
+
+var errBadReplay = errors.New("not a snake replay")
+
+func (r *Replay) UnmarshalBinary(b []byte) error {
+	if len(b) < len(replayMagic) || string(b[:len(replayMagic)]) != replayMagic {
+		return errBadReplay
+	}
+	b = b[len(replayMagic):]
+	seed, n := binary.Varint(b)
+	if n <= 0 {
+		return errBadReplay
+	}
+	b = b[n:]
+	var head [3]uint64
+	for i := range head {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errBadReplay
+		}
+		head[i] = v
+		b = b[n:]
+	}
+	w, h, ticks := head[0], head[1], head[2]
+	if !plausible(w, h) || ticks > 10_000_000 {
+		return fmt.Errorf("%w: implausible %dx%d board or %d ticks", errBadReplay, w, h, ticks)
+	}
+
+	d, k := binary.Uvarint(b)
+	if k <= 0 || k+1 >= len(b) || d > uint64(Hard) || b[k] > 1 || b[k+1] > 1 {
+		return errBadReplay
+	}
+	mode := Mode{Difficulty: Difficulty(d), Wrap: b[k] == 1, Levels: b[k+1] == 1}
+	b = b[k+2:]
+
+	var levels []*Level
+	if mode.Levels {
+		n, k := binary.Uvarint(b)
+		if k <= 0 || n == 0 || n > uint64(len(b)) {
+			return errBadReplay
+		}
+		b = b[k:]
+		for range n {
+			size, k := binary.Uvarint(b)
+			if k <= 0 || size > uint64(len(b)-k) {
+				return errBadReplay
+			}
+			l, err := ParseLevel(string(b[k : k+int(size)]))
+			if err != nil {
+				return fmt.Errorf("%w: %w", errBadReplay, err)
+			}
+			levels = append(levels, l)
+			b = b[k+int(size):]
+		}
+	}
+
+	var resizes []Resize
+	count, k := binary.Uvarint(b)
+	if k <= 0 || count > uint64(len(b)) {
+		return errBadReplay
+	}
+	b = b[k:]
+	for range count {
+		var rs [3]uint64
+		for i := range rs {
+			v, k := binary.Uvarint(b)
+			if k <= 0 {
+				return errBadReplay
+			}
+			rs[i] = v
+			b = b[k:]
+		}
+		if rs[0] > ticks || !plausible(rs[1], rs[2]) {
+			return errBadReplay
+		}
+		resizes = append(resizes, Resize{At: int(rs[0]), W: int(rs[1]), H: int(rs[2])})
+	}
+
+	inputs := make([]Dir, 0, ticks)
+	for len(b) > 0 {
+		gap, n := binary.Uvarint(b)
+		if n <= 0 || n >= len(b) || gap > ticks-uint64(len(inputs)) {
+			return errBadReplay
+		}
+		in := Dir(b[n])
+		if in < Up || in > Right {
+			return errBadReplay
+		}
+		inputs = append(inputs, make([]Dir, gap)...)
+		inputs = append(inputs, in)
+		b = b[n+1:]
+	}
+	if uint64(len(inputs)) > ticks {
+		return errBadReplay
+	}
+	inputs = append(inputs, make([]Dir, ticks-uint64(len(inputs)))...)
+
+	*r = Replay{Seed: seed, W: int(w), H: int(h), Mode: mode, Levels: levels, Inputs: inputs, Resizes: resizes}
+	return nil
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func plausible(w, h uint64) bool {
+	return w >= 4 && h >= 1 && w <= 1000 && h <= 1000
+}
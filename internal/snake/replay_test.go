@@ -0,0 +1,111 @@
+package snake
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// play records a game of r.Mode until it ends or runs out of ticks,
+// resizing an open board once along the way. The snake heads for the food
+// and turns at random now and then, but never into a crash it can see.
+func play(r *Replay, ticks int, seed int64) *Game {
+	inputs := rand.New(rand.NewSource(seed))
+	g := r.Start()
+	for i := 0; i < ticks && !g.Over; i++ {
+		if i == 40 && !r.Mode.Levels {
+			r.Resizes = append(r.Resizes, Resize{At: i, W: g.W + 6, H: g.H + 2})
+		}
+		dirs := []Dir{Up, Down, Left, Right}
+		inputs.Shuffle(len(dirs), func(a, b int) { dirs[a], dirs[b] = dirs[b], dirs[a] })
+		if inputs.Intn(8) != 0 {
+			head := g.Head()
+			dist := func(d Dir) int {
+				p := d.Move(head)
+				return abs(p.X-g.Food.X) + abs(p.Y-g.Food.Y)
+			}
+			slices.SortStableFunc(dirs, func(a, b Dir) int { return dist(a) - dist(b) })
+		}
+		in := None
+		for _, d := range dirs {
+			try := Restore(g.Snapshot(), rand.New(rand.NewSource(0)))
+			if g.Turnable(d) && try.Step(d) != Crashed {
+				in = d
+				break
+			}
+		}
+		r.Inputs = append(r.Inputs, in)
+		r.Step(g, i)
+	}
+	return g
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	level, err := ParseLevel(testLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		r    Replay
+	}{
+		{"normal", Replay{Seed: 1, W: 20, H: 10}},
+		{"hard wrap", Replay{Seed: -7, W: 30, H: 12, Mode: Mode{Difficulty: Hard, Wrap: true}}},
+		{"levels", Replay{Seed: 42, W: 20, H: 10, Mode: Mode{Difficulty: Easy, Levels: true}, Levels: []*Level{level, level}}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.r
+			g := play(&r, 2000, int64(i))
+			if g.Score == 0 {
+				t.Fatal("the recorded game never scored")
+			}
+
+			data, err := r.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var back Replay
+			if err := back.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(back, r) {
+				t.Fatalf("decoded replay differs:\n got %+v\nwant %+v", back, r)
+			}
+
+			end := back.At(back.Len())
+			if end.Score != g.Score || end.Over != g.Over || !reflect.DeepEqual(end.Snake, g.Snake) {
+				t.Errorf("replayed to score %d, over %v; played score %d, over %v", end.Score, end.Over, g.Score, g.Over)
+			}
+			if tl := back.Timeline(); len(tl) != back.Len()+1 {
+				t.Errorf("Timeline has %d entries for %d ticks", len(tl), back.Len())
+			}
+		})
+	}
+}
+
+func TestReplayRejectsGarbage(t *testing.T) {
+	good, _ := (&Replay{Seed: 1, W: 20, H: 10, Inputs: []Dir{None, Up, None, Left}}).MarshalBinary()
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"magic only":  good[:4],
+		"wrong magic": append([]byte("PNG!"), good[4:]...),
+		"old version": append([]byte("SNK3"), good[4:]...),
+		"bad input":   append(append([]byte(nil), good...), 0, 9),
+		"extra ticks": append(append([]byte(nil), good...), 200, 1),
+	} {
+		var r Replay
+		if err := r.UnmarshalBinary(data); !errors.Is(err, errBadReplay) {
+			t.Errorf("%s: err = %v, want errBadReplay", name, err)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Package snake holds the rules of Snake with no terminal attached: a
+// board, a snake, food, and a Step that advances the game one tick for
+// one input. Given the same Rand and the same inputs, a game always plays
+// out the same way.
+package snake
+
+import "time"
+
+// TickInterval is how long a step takes at the start of a Normal game.
+const TickInterval = 120 * time.Millisecond
+
+// Point is a cell on the board, from the top-left corner.
+type Point struct{ X, Y int }
+
+// Dir is a heading, or None for "keep going".
+type Dir int
+
+const (
+	None Dir = iota
+	Up
+	Down
+	Left
+	Right
+)
+
+func (d Dir) Opposite() Dir {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	case Right:
+		return Left
+	}
+	return None
+}
+
+// Move returns the cell next to p in direction d.
+func (d Dir) Move(p Point) Point {
+	switch d {
+	case Up:
+		return Point{p.X, p.Y - 1}
+	case Down:
+		return Point{p.X, p.Y + 1}
+	case Left:
+		return Point{p.X - 1, p.Y}
+	case Right:
+		return Point{p.X + 1, p.Y}
+	}
+	return p
+}
+
+// Rand is where food placement comes from; *rand.Rand will do.
+type Rand interface {
+	Intn(n int) int
+}
+
+// State is a game at one moment: everything needed to draw it or pick it
+// up again.
+type State struct {
+	W, H int
+	Mode Mode
+	// Levels are played in order, LevelNo counting from 0; Eaten is the
+	// food eaten on the current one. An open board has no levels.
+	Levels  []*Level
+	LevelNo int
+	Eaten   int
+	// Won is set when the last level is cleared.
+	Won bool
+	// Snake runs from head to tail.
+	Snake []Point
+	Dir   Dir
+	Food  Point
+	Score int
+	Over  bool
+}
+
+// Event is what a Step did.
+type Event int
+
+const (
+	// Idle means the game was already over.
+	Idle Event = iota
+	Moved
+	Ate
+	Crashed
+	// Cleared means the snake ate the last food of its level and has moved
+	// on to the next one, or won.
+	Cleared
+)
+
+// Level is the level being played, or nil on an open board.
+func (st State) Level() *Level {
+	if st.Levels == nil {
+		return nil
+	}
+	return st.Levels[st.LevelNo]
+}
+
+type Game struct {
+	State
+	rng Rand
+}
+
+// New starts a game of mode on a w×h board, with the snake in the middle
+// heading right.
+func New(w, h int, mode Mode, rng Rand) *Game {
+	cx, cy := w/2, h/2
+	g := &Game{
+		State: State{
+			W:    w,
+			H:    h,
+			Mode: mode,
+			Dir:  Right,
+		},
+		rng: rng,
+	}
+	for i := range mode.StartLen() {
+		g.Snake = append(g.Snake, Point{cx - i, cy})
+	}
+	g.spawnFood()
+	return g
+}
+
+// NewLevels starts a game of mode that plays levels in order.
+func NewLevels(levels []*Level, mode Mode, rng Rand) *Game {
+	g := &Game{State: State{Mode: mode, Levels: levels}, rng: rng}
+	g.enter(0)
+	return g
+}
+
+// enter puts a fresh snake on level n.
+func (g *Game) enter(n int) {
+	l := g.Levels[n]
+	g.LevelNo, g.Eaten = n, 0
+	g.W, g.H = l.W, l.H
+	g.Dir = Right
+	g.Snake = g.Snake[:0]
+	for i := range g.Mode.StartLen() {
+		g.Snake = append(g.Snake, Point{l.Start.X - i, l.Start.Y})
+	}
+	g.spawnFood()
+}
+
+// Restore picks up a game from st. rng may be nil if the game will only be
+// drawn, never stepped.
+func Restore(st State, rng Rand) *Game {
+	st.Snake = append([]Point(nil), st.Snake...)
+	return &Game{State: st, rng: rng}
+}
+
+// Snapshot copies the game's state.
+func (g *Game) Snapshot() State {
+	st := g.State
+	st.Snake = append([]Point(nil), g.Snake...)
+	return st
+}
+
+func (g *Game) Head() Point {
+	return g.Snake[0]
+}
+
+// Turnable reports whether the snake may turn to d next step: it can't
+// reverse into itself.
+func (g *Game) Turnable(d Dir) bool {
+	return d != None && d != g.Dir.Opposite()
+}
+
+// Step advances one tick, turning first if in is a legal turn.
+func (g *Game) Step(in Dir) Event {
+	if g.Over {
+		return Idle
+	}
+	if g.Turnable(in) {
+		g.Dir = in
+	}
+
+	head, ok := g.move(g.Head(), g.Dir)
+	if !ok {
+		g.Over = true
+		return Crashed
+	}
+	for _, p := range g.Snake {
+		if p == head {
+			g.Over = true
+			return Crashed
+		}
+	}
+
+	g.Snake = append([]Point{head}, g.Snake...)
+	if head != g.Food {
+		g.Snake = g.Snake[:len(g.Snake)-1]
+		return Moved
+	}
+	g.Score++
+	g.Eaten++
+	if l := g.Level(); l != nil && l.Target > 0 && g.Eaten >= l.Target {
+		if g.LevelNo == len(g.Levels)-1 {
+			g.Over, g.Won = true, true
+		} else {
+			g.enter(g.LevelNo + 1)
+		}
+		return Cleared
+	}
+	g.spawnFood()
+	return Ate
+}
+
+// move is where a head at p ends up heading d, through any portal, and
+// whether it's still on the board and clear of walls.
+func (st State) move(p Point, d Dir) (Point, bool) {
+	p = d.Move(p)
+	if st.Mode.Wrap || st.Levels != nil {
+		p.X = (p.X + st.W) % st.W
+		p.Y = (p.Y + st.H) % st.H
+	}
+	if to, ok := st.Level().Portal(p); ok {
+		p = to
+	}
+	return p, p.X >= 0 && p.X < st.W && p.Y >= 0 && p.Y < st.H && !st.Level().Wall(p)
+}
+
+// Resize changes the board to w×h, or as near as it can without cutting
+// off the snake. Food left outside the board is placed again. Levels keep
+// their size.
+func (g *Game) Resize(w, h int) {
+	if g.Levels != nil {
+		return
+	}
+	for _, p := range g.Snake {
+		w, h = max(w, p.X+1), max(h, p.Y+1)
+	}
+	g.W, g.H = w, h
+	if g.Food.X >= w || g.Food.Y >= h {
+		g.spawnFood()
+	}
+}
+
+// spawnFood puts food on a random free cell. A snake filling the whole
+// board has nowhere left to go, and the game ends.
+func (g *Game) spawnFood() {
+	level := g.Level()
+	// A snake going through a portal is on it, but a portal is never free.
+	occupied := make(map[Point]bool, len(g.Snake))
+	for _, p := range g.Snake {
+		if !level.blocked(p) {
+			occupied[p] = true
+		}
+	}
+	free := g.W * g.H
+	if level != nil {
+		free -= len(level.walls) + len(level.portals)
+	}
+	if len(occupied) >= free {
+		g.Over = true
+		return
+	}
+	for {
+		p := Point{g.rng.Intn(g.W), g.rng.Intn(g.H)}
+		if !occupied[p] && !level.blocked(p) {
+			g.Food = p
+			return
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package snake
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fixed deals out its values in turn, then zeroes.
+type fixed []int
+
+func (f *fixed) Intn(n int) int {
+	if len(*f) == 0 {
+		return 0
+	}
+	v := (*f)[0]
+	*f = (*f)[1:]
+	return v % n
+}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  Mode
+		snake []Point
+		dir   Dir
+		in    Dir
+		want  Event
+		head  Point
+		len   int
+	}{
+		{
+			name:  "keeps going",
+			snake: []Point{{2, 2}, {1, 2}, {0, 2}},
+			dir:   Right,
+			want:  Moved,
+			head:  Point{3, 2},
+			len:   3,
+		},
+		{
+			name:  "turns",
+			snake: []Point{{2, 2}, {1, 2}, {0, 2}},
+			dir:   Right,
+			in:    Down,
+			want:  Moved,
+			head:  Point{2, 3},
+			len:   3,
+		},
+		{
+			name:  "can't reverse",
+			snake: []Point{{2, 2}, {1, 2}, {0, 2}},
+			dir:   Right,
+			in:    Left,
+			want:  Moved,
+			head:  Point{3, 2},
+			len:   3,
+		},
+		{
+			name:  "right wall",
+			snake: []Point{{4, 2}, {3, 2}, {2, 2}},
+			dir:   Right,
+			want:  Crashed,
+		},
+		{
+			name:  "top wall",
+			snake: []Point{{2, 0}, {2, 1}, {2, 2}},
+			dir:   Up,
+			want:  Crashed,
+		},
+		{
+			name:  "wraps off the right",
+			mode:  Mode{Wrap: true},
+			snake: []Point{{4, 2}, {3, 2}, {2, 2}},
+			dir:   Right,
+			want:  Moved,
+			head:  Point{0, 2},
+			len:   3,
+		},
+		{
+			name:  "wraps off the top",
+			mode:  Mode{Wrap: true},
+			snake: []Point{{2, 0}, {2, 1}, {2, 2}},
+			dir:   Up,
+			want:  Moved,
+			head:  Point{2, 4},
+			len:   3,
+		},
+		{
+			name:  "into its body",
+			snake: []Point{{2, 2}, {3, 2}, {3, 3}, {2, 3}, {1, 3}},
+			dir:   Left,
+			in:    Down,
+			want:  Crashed,
+		},
+		{
+			// The tail would move out of the way this tick, but the cell
+			// still counts, as it always has.
+			name:  "into its tail",
+			snake: []Point{{2, 2}, {3, 2}, {3, 3}, {2, 3}},
+			dir:   Left,
+			in:    Down,
+			want:  Crashed,
+		},
+		{
+			name:  "eats and grows",
+			snake: []Point{{1, 1}, {0, 1}, {0, 0}},
+			dir:   Right,
+			want:  Ate,
+			head:  Point{2, 1},
+			len:   4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := Restore(State{W: 5, H: 5, Mode: tt.mode, Snake: tt.snake, Dir: tt.dir, Food: Point{2, 1}}, &fixed{4, 4})
+			got := g.Step(tt.in)
+			if got != tt.want {
+				t.Fatalf("Step = %v, want %v", got, tt.want)
+			}
+			if tt.want == Crashed {
+				if !g.Over {
+					t.Error("crashed but not over")
+				}
+				if !reflect.DeepEqual(g.Snake, tt.snake) {
+					t.Errorf("snake moved on crashing: %v", g.Snake)
+				}
+				return
+			}
+			if g.Over {
+				t.Error("game over")
+			}
+			if g.Head() != tt.head || len(g.Snake) != tt.len {
+				t.Errorf("head %v, length %d; want %v, %d", g.Head(), len(g.Snake), tt.head, tt.len)
+			}
+			if tt.want == Ate {
+				if g.Score != 1 {
+					t.Errorf("Score = %d, want 1", g.Score)
+				}
+				if g.Food != (Point{4, 4}) {
+					t.Errorf("Food = %v, want it placed again at {4 4}", g.Food)
+				}
+			}
+		})
+	}
+}
+
+func TestFoodAvoidsSnake(t *testing.T) {
+	// The first two tries land on the snake, so the third is taken.
+	g := Restore(State{W: 5, H: 5, Snake: []Point{{1, 1}, {0, 1}, {0, 0}}, Dir: Right}, &fixed{0, 0, 1, 1, 3, 3})
+	g.spawnFood()
+	if g.Food != (Point{3, 3}) {
+		t.Errorf("Food = %v, want {3 3}", g.Food)
+	}
+}
+
+func TestStepOver(t *testing.T) {
+	g := Restore(State{W: 5, H: 5, Snake: []Point{{2, 2}}, Dir: Right, Over: true}, nil)
+	if got := g.Step(Up); got != Idle {
+		t.Errorf("Step = %v, want Idle", got)
+	}
+	if g.Head() != (Point{2, 2}) {
+		t.Errorf("snake moved after the game ended")
+	}
+}
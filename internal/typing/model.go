@@ -0,0 +1,435 @@
+package typing
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/koossaayy/ssh-portal/internal/content"
+	"github.com/koossaayy/ssh-portal/internal/game"
+	"github.com/koossaayy/ssh-portal/internal/scores"
+)
+
+// Name is the test's key in the score store, where words per minute are
+// the score and missed keys are tallied.
+const Name = "typing"
+
+// AccuracyKey is where each test's accuracy is kept, in percent.
+const AccuracyKey = "typing-accuracy"
+
+// Info puts the typing test on the Games menu.
+var Info = game.Info{
+	Name: "Typing Test",
+	Icon: "📝",
+	Desc: "Type the portal's own words, against the clock",
+	New: func(s game.Session) game.Game {
+		return New(s.Renderer, s.Width, s.Height, s.Player, s.Content)
+	},
+	Boards: func(*scores.Store, time.Time) []game.Board {
+		return []game.Board{
+			{Name: "Typing · words per minute", Key: Name},
+			{Name: "Typing · accuracy", Key: AccuracyKey, Unit: "%"},
+		}
+	},
+}
+
+// keyboard is the heatmap's layout; shifted characters count against the
+// key they're on.
+var keyboard = []string{"1234567890-", "qwertyuiop", "asdfghjkl;'", "zxcvbnm,./"}
+
+var shifted = strings.NewReplacer(
+	"!", "1", "@", "2", "#", "3", "$", "4", "%", "5", "^", "6", "&", "7", "*", "8", "(", "9", ")", "0", "_", "-",
+	":", ";", `"`, "'", "<", ",", ">", ".", "?", "/",
+)
+
+// keyOf is the key c is typed on.
+func keyOf(c byte) string {
+	if c == ' ' {
+		return "space"
+	}
+	return shifted.Replace(strings.ToLower(string(c)))
+}
+
+// tickMsg keeps the clock going; gen drops ticks from an earlier test.
+type tickMsg struct{ gen int }
+
+func (m Model) tick() tea.Cmd {
+	gen := m.gen
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return tickMsg{gen: gen}
+	})
+}
+
+// Model is one typing test after another. The clock starts on the first
+// key; mistakes stay on screen until they're fixed with backspace, and
+// count against accuracy even then.
+type Model struct {
+	renderer *lipgloss.Renderer
+	width    int
+	height   int
+	rng      *rand.Rand
+	passages []Passage
+	test     Passage
+	typed    []byte
+	started  time.Time
+	// elapsed is set when the test is done.
+	elapsed time.Duration
+	done    bool
+	// keys and misses count every key pressed against the text; missed
+	// counts misses by key, and heat is what the heatmap shows.
+	keys   int
+	misses int
+	missed map[string]int
+	heat   map[string]int
+	gen    int
+	quit   bool
+
+	// player is nil for anonymous visitors, whose results aren't kept.
+	player  *scores.Player
+	best    int
+	newBest bool
+	saveErr string
+}
+
+func New(r *lipgloss.Renderer, w, h int, player *scores.Player, c *content.Content) Model {
+	m := Model{
+		renderer: r,
+		width:    w,
+		height:   h,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		passages: Passages(c),
+		player:   player,
+		best:     player.Best(Name),
+	}
+	m.reset()
+	return m
+}
+
+func (m *Model) reset() {
+	m.test = test(m.passages, m.rng)
+	m.typed = nil
+	m.started = time.Time{}
+	m.elapsed = 0
+	m.done = false
+	m.keys, m.misses = 0, 0
+	m.missed = map[string]int{}
+	m.heat = nil
+	m.gen++
+	m.newBest = false
+	m.saveErr = ""
+}
+
+// Done reports whether the visitor has left the test.
+func (m Model) Done() bool {
+	return m.quit
+}
+
+func (m Model) ScoreKey() string {
+	return Name
+}
+
+// correct counts the characters typed right so far.
+func (m Model) correct() int {
+	n := 0
+	for i, c := range m.typed {
+		if c == m.test.Text[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// wpm is words per minute, a word being five characters typed right.
+func (m Model) wpm(d time.Duration) int {
+	if d < time.Second {
+		return 0
+	}
+	return int(float64(m.correct()) / 5 / d.Minutes())
+}
+
+// accuracy is the share of keys pressed that were right, in percent.
+func (m Model) accuracy() int {
+	if m.keys == 0 {
+		return 100
+	}
+	return (m.keys - m.misses) * 100 / m.keys
+}
+
+// finish ends the test and saves the result: the misses, the words per
+// minute and the accuracy, even when it was too slow to score.
+func (m *Model) finish() {
+	m.done = true
+	m.elapsed = time.Since(m.started)
+	m.gen++
+	wpm := m.wpm(m.elapsed)
+	// Anonymous visitors' results aren't kept, so none is a new best.
+	if wpm > m.best {
+		m.best = wpm
+		m.newBest = m.player != nil
+	}
+	heat, err := m.player.Tally(Name, m.missed)
+	if err == nil {
+		err = m.player.Record(Name, wpm)
+	}
+	if err == nil {
+		err = m.player.Record(AccuracyKey, m.accuracy())
+	}
+	if err != nil {
+		m.saveErr = err.Error()
+		heat = m.missed
+	}
+	m.heat = heat
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *content.Content:
+		m.passages = Passages(msg)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case tickMsg:
+		if msg.gen != m.gen || m.done {
+			return m, nil
+		}
+		return m, m.tick()
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.quit = true
+			return m, nil
+		case tea.KeyTab:
+			m.reset()
+			return m, nil
+		case tea.KeyEnter:
+			if m.done {
+				m.reset()
+			}
+			return m, nil
+		}
+		// Pasting isn't typing.
+		if m.done || msg.Paste {
+			return m, nil
+		}
+		switch msg.Type {
+		case tea.KeyBackspace:
+			if len(m.typed) > 0 {
+				m.typed = m.typed[:len(m.typed)-1]
+			}
+			return m, nil
+		case tea.KeySpace:
+			return m.press(' ')
+		case tea.KeyRunes:
+			if len(msg.Runes) == 1 && msg.Runes[0] >= ' ' && msg.Runes[0] <= '~' {
+				return m.press(byte(msg.Runes[0]))
+			}
+		}
+	}
+	return m, nil
+}
+
+// press types c at the cursor.
+func (m Model) press(c byte) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.started.IsZero() {
+		m.started = time.Now()
+		cmd = m.tick()
+	}
+	want := m.test.Text[len(m.typed)]
+	m.keys++
+	if c != want {
+		m.misses++
+		m.missed[keyOf(want)]++
+	}
+	m.typed = append(m.typed, c)
+	if len(m.typed) == len(m.test.Text) {
+		m.finish()
+		return m, nil
+	}
+	return m, cmd
+}
+
+// wrap breaks s into lines of at most w bytes plus the space after them,
+// between words where it can, as [start, end) offsets.
+func wrap(s string, w int) [][2]int {
+	var lines [][2]int
+	for start := 0; start < len(s); {
+		end := min(start+w, len(s))
+		if end < len(s) {
+			if sp := strings.LastIndexByte(s[start:end+1], ' '); sp > 0 {
+				end = start + sp + 1
+			}
+		}
+		lines = append(lines, [2]int{start, end})
+		start = end
+	}
+	return lines
+}
+
+func (m Model) View() string {
+	r := m.renderer
+
+	pink   := lipgloss.Color("#FF79C6")
+	cyan   := lipgloss.Color("#8BE9FD")
+	yellow := lipgloss.Color("#F1FA8C")
+	green  := lipgloss.Color("#50FA7B")
+	orange := lipgloss.Color("#FFB86C")
+	red    := lipgloss.Color("#FF5555")
+	fg     := lipgloss.Color("#F8F8F2")
+	bg     := lipgloss.Color("#282A36")
+	subtle := lipgloss.Color("#6272A4")
+	purple := lipgloss.Color("#9B72CF")
+	line   := lipgloss.Color("#44475A")
+
+	footStyle := r.NewStyle().Foreground(subtle).Italic(true)
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  📝 Typing test — the portal's own words"))
+	sb.WriteString("\n\n")
+
+	boxW := max(min(m.width-4, 76), 30)
+	text := m.test.Text
+	var body []string
+	for _, l := range wrap(text, boxW-7) {
+		var row strings.Builder
+		for i := l[0]; i < l[1]; i++ {
+			c := string(text[i])
+			switch {
+			case i < len(m.typed) && m.typed[i] == text[i]:
+				row.WriteString(r.NewStyle().Foreground(fg).Render(c))
+			case i < len(m.typed):
+				row.WriteString(r.NewStyle().Foreground(bg).Background(red).Render(c))
+			case i == len(m.typed):
+				row.WriteString(r.NewStyle().Foreground(bg).Background(yellow).Render(c))
+			default:
+				row.WriteString(r.NewStyle().Foreground(subtle).Render(c))
+			}
+		}
+		body = append(body, row.String())
+	}
+	sb.WriteString(r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(purple).
+		Padding(1, 2).
+		MarginLeft(2).
+		Width(boxW - 2).
+		Render(strings.Join(body, "\n")))
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(subtle).Italic(true).Render("    — from " + m.test.From))
+	sb.WriteString("\n\n")
+
+	stat := func(label string, value string, color lipgloss.Color) string {
+		return r.NewStyle().Foreground(subtle).Render(label+" ") + r.NewStyle().Foreground(color).Bold(true).Render(value)
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+	}
+
+	if !m.done {
+		if m.started.IsZero() {
+			sb.WriteString(r.NewStyle().Foreground(cyan).Render("  Start typing: the clock starts with your first key."))
+		} else {
+			d := time.Since(m.started)
+			sb.WriteString("  " + strings.Join([]string{
+				stat("⏱", clock(d), fg),
+				stat("WPM", fmt.Sprint(m.wpm(d)), pink),
+				stat("accuracy", fmt.Sprintf("%d%%", m.accuracy()), cyan),
+			}, "  •  "))
+		}
+		sb.WriteString("\n\n")
+		sb.WriteString(footStyle.Render("  backspace to fix a mistake  •  tab for another text  •  esc to go back to menu"))
+		return sb.String()
+	}
+
+	sb.WriteString("  " + strings.Join([]string{
+		stat("WPM", fmt.Sprint(m.wpm(m.elapsed)), pink),
+		stat("accuracy", fmt.Sprintf("%d%%", m.accuracy()), cyan),
+		stat("time", clock(m.elapsed), fg),
+		stat("best", fmt.Sprint(m.best), yellow),
+	}, "  •  "))
+	switch {
+	case m.saveErr != "":
+		sb.WriteString("  " + r.NewStyle().Foreground(red).Render("Couldn't save your result: "+m.saveErr))
+	case m.newBest:
+		sb.WriteString("  " + r.NewStyle().Foreground(green).Bold(true).Render("🏆 New best!"))
+	}
+	sb.WriteString("\n\n")
+
+	// The heatmap colours each key by how often it was missed, next to
+	// the key missed most.
+	most := 0
+	for _, n := range m.heat {
+		most = max(most, n)
+	}
+	levels := []lipgloss.Color{yellow, orange, pink, red}
+	key := func(k, label string) string {
+		style := r.NewStyle().Foreground(subtle).Background(line)
+		if n := m.heat[k]; n > 0 {
+			style = r.NewStyle().Foreground(bg).Background(levels[min((n*len(levels)-1)/most, len(levels)-1)]).Bold(true)
+		}
+		return style.Render(label)
+	}
+	title := "MISSED KEYS, THIS TEST"
+	if m.player != nil && m.saveErr == "" {
+		title = "MISSED KEYS, ALL YOUR TESTS"
+	}
+	sb.WriteString(r.NewStyle().Foreground(subtle).Render("  " + title))
+	sb.WriteString("\n")
+	for i, row := range keyboard {
+		var keys []string
+		for _, k := range row {
+			keys = append(keys, key(string(k), " "+string(k)+" "))
+		}
+		sb.WriteString("  " + strings.Repeat(" ", i*2) + strings.Join(keys, " "))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("  " + strings.Repeat(" ", 12) + key("space", strings.Repeat(" ", 11)+"space"+strings.Repeat(" ", 11)))
+	sb.WriteString("\n")
+
+	if worst := mostMissed(m.heat, 3); len(worst) > 0 {
+		sb.WriteString(r.NewStyle().Foreground(subtle).Render("  Missed most: " + strings.Join(worst, ", ")))
+		sb.WriteString("\n")
+	}
+	if m.player == nil {
+		sb.WriteString(r.NewStyle().Foreground(subtle).Render("  Connect with an SSH key to keep your results."))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(footStyle.Render("  enter or tab for another text  •  esc to go back to menu"))
+	return sb.String()
+}
+
+// mostMissed lists the n keys in heat missed most, with how often.
+func mostMissed(heat map[string]int, n int) []string {
+	keys := make([]string, 0, len(heat))
+	for k, v := range heat {
+		if v > 0 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if heat[keys[i]] != heat[keys[j]] {
+			return heat[keys[i]] > heat[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	var out []string
+	for _, k := range keys[:min(n, len(keys))] {
+		out = append(out, fmt.Sprintf("%s ×%d", k, heat[k]))
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+// Package typing is a typing test on the portal's own words: passages
+// from the projects, the quotes and the about page, timed for words per
+// minute and accuracy, with a heatmap of the keys that were missed.
+package typing
+
+import (
+	"math/rand"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/koossaayy/ssh-portal/internal/content"
+)
+
+// Passage is text to type and where it comes from.
+type Passage struct {
+	Text string
+	From string
+}
+
+// maxPassage is the most text a passage takes from one source, cut at the
+// end of a sentence; minTest is the least a test asks for, so short
+// passages are run together.
+const (
+	maxPassage = 220
+	minTest    = 100
+)
+
+// Passages picks out everything in c worth typing.
+func Passages(c *content.Content) []Passage {
+	var out []Passage
+	add := func(text, from string) {
+		if text = plain(text); len(strings.Fields(text)) >= 4 {
+			out = append(out, Passage{Text: text, From: from})
+		}
+	}
+	for _, p := range c.Projects {
+		add(upTo(p.Desc, maxPassage), "Portfolio · "+p.Name)
+	}
+	for _, q := range c.Quotes {
+		q = plain(q)
+		if len(q) > 1 && q[0] == '"' && q[len(q)-1] == '"' {
+			q = q[1 : len(q)-1]
+		}
+		add(q, "Quotes")
+	}
+	add(c.About.Greeting, "About")
+	for _, s := range c.About.Sections {
+		add(upTo(s.Body, maxPassage), "About · "+s.Title)
+	}
+	return out
+}
+
+// test joins randomly picked passages into one at least minTest long.
+func test(passages []Passage, rng *rand.Rand) Passage {
+	if len(passages) == 0 {
+		return Passage{Text: "The quick brown fox jumps over the lazy dog.", From: "the typist's classic"}
+	}
+	var texts, from []string
+	n := 0
+	for _, i := range rng.Perm(len(passages)) {
+		p := passages[i]
+		texts = append(texts, p.Text)
+		if !slices.Contains(from, p.From) {
+			from = append(from, p.From)
+		}
+		if n += len(p.Text) + 1; n > minTest {
+			break
+		}
+	}
+	return Passage{Text: strings.Join(texts, " "), From: strings.Join(from, ", ")}
+}
+
+var (
+	link     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markup   = strings.NewReplacer("**", "", "__", "", "`", "")
+	typeable = strings.NewReplacer("‘", "'", "’", "'", "“", `"`, "”", `"`, "…", "...", "—", "-", "–", "-")
+)
+
+// plain is s as it can be typed on any keyboard: no markdown, no emoji,
+// straight quotes, and single spaces.
+func plain(s string) string {
+	s = typeable.Replace(markup.Replace(link.ReplaceAllString(s, "$1")))
+	s = strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' {
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// upTo is as many of s's sentences as fit in n bytes. A first sentence
+// that's already longer is cut at the last word that fits.
+func upTo(s string, n int) string {
+	s = plain(s)
+	end := 0
+	for i := range len(s) {
+		if i+1 < len(s) && !(strings.ContainsRune(".!?", rune(s[i])) && s[i+1] == ' ') {
+			continue
+		}
+		if end > 0 && i+1 > n {
+			break
+		}
+		end = i + 1
+	}
+	if end > n {
+		if sp := strings.LastIndexByte(s[:n], ' '); sp > 0 {
+			end = sp
+		}
+	}
+	return s[:end]
+}
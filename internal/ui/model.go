@@ -3,13 +3,20 @@ package ui
 import (
 	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/koossaayy/ssh-portal/internal/about"
+	"github.com/koossaayy/ssh-portal/internal/content"
 	"github.com/koossaayy/ssh-portal/internal/game"
+	"github.com/koossaayy/ssh-portal/internal/identity"
 	"github.com/koossaayy/ssh-portal/internal/portfolio"
+	"github.com/koossaayy/ssh-portal/internal/scores"
+	"github.com/koossaayy/ssh-portal/internal/servers"
 )
 
 type view int
@@ -18,7 +25,15 @@ const (
 	viewHome view = iota
 	viewAbout
 	viewPortfolio
+	viewServers
+	// viewGames is the menu of registered games, and viewGame the one
+	// picked from it.
+	viewGames
 	viewGame
+	viewArena
+	viewSpectate
+	viewReplays
+	viewLeaderboard
 )
 
 const banner = `
@@ -29,26 +44,6 @@ const banner = `
  ██║  ██╗╚██████╔╝╚██████╔╝███████║███████║██║  ██║██║  ██║   ██║      ██║   
  ╚═╝  ╚═╝ ╚═════╝  ╚═════╝ ╚══════╝╚══════╝╚═╝  ╚═╝╚═╝  ╚═╝   ╚═╝      ╚═╝  `
 
-var pirateQuotes = []string{
-	"\"Not all treasure is silver and gold, mate.\" 🏴‍☠️",
-	"\"This is the day you will always remember as the day you almost caught Captain Jack Sparrow.\" 🦜",
-	"\"Why is the rum always gone? ...Oh, that's why.\" 🥃",
-	"\"The problem is not the problem. The problem is your attitude about the problem.\" ☠️",
-	"\"Me? I'm dishonest. And a dishonest man you can always trust to be dishonest.\" 🧭",
-	"\"Nobody move! I dropped me brain.\" 💀",
-	"\"I love those moments. I like to wave at them as they pass by.\" 🌊",
-	"\"Did everyone see that? Because I will not be doing it again.\" 🪝",
-	"\"You seem somewhat familiar. Have I threatened you before?\" ⚔️",
-	"\"Wherever we want to go, we go.\" 🗺️",
-	"\"“UP IS DOWN”? Well that's just maddeningly unhelpful. Why are these things never clear?\" 😕",
-	"\"I've got a jar of dirt\" ⚔️",
-	"\"Crazy people don't know they're crazy. I know that I'm crazy, therefore I'm not crazy. Isn't that crazy?\" 😀",
-	"\"Why fight when you can negotiate?\" 🫙",
-	"\"Stop blowing holes in my ship!!\" ⚓",
-	"\"No! Not good! Stop! Not good! What are you doing? You burned all the food, the shade... the rum\" 🍺",
-
-}
-
 type menuItem struct {
 	label string
 	icon  string
@@ -59,7 +54,12 @@ type menuItem struct {
 var menuItems = []menuItem{
 	{"About & Welcome", "👋", "Who is this mysterious person?", viewAbout},
 	{"Portfolio", "🚀", "Projects, work, and cool stuff", viewPortfolio},
-	{"Play Snake!", "🐍", "Take a break, you deserve it", viewGame},
+	{"Server Directory", "🖧 ", "SSH into the machines of the realm", viewServers},
+	{"Games", "🎮", "Take a break, you deserve it", viewGames},
+	{"Snake Arena", "🐉", "Multiplayer Snake with everyone online", viewArena},
+	{"Watch Games", "👀", "Spectate Snake games other visitors are playing", viewSpectate},
+	{"Replays", "📼", "Saved Snake games, played back", viewReplays},
+	{"Leaderboard", "🏆", "Top scores in every game: all-time, this week, today", viewLeaderboard},
 }
 
 type MainModel struct {
@@ -68,26 +68,96 @@ type MainModel struct {
 	height    int
 	cursor    int
 	current   view
+	// gameCursor is the cursor on the Games menu, and game the game
+	// picked from it. played is the board its scores went on, for the
+	// leaderboard to open on.
+	gameCursor int
+	game       game.Game
+	played     string
 	portfolio portfolio.Model
-	game      game.Model
+	servers   servers.Model
+	arena     game.Arena
+	spectate  game.Spectator
+	replays   game.Replays
+	board     game.Leaderboard
+	about     about.About
 	quote     string
+	content   *content.Content
+	// who is the connected visitor, for sections that greet them or keep
+	// things per person.
+	who    identity.Identity
+	scores *scores.Store
+	player *scores.Player
+	seat   *game.Seat
+	// start is run first, for sessions that open straight into a section.
+	start tea.Cmd
+	// demo is a game playing itself, shown while attract is set: after the
+	// menu has sat on a game with a demo, or on Games, for attractAfter
+	// since lastKey.
+	demo    game.Game
+	attract bool
+	lastKey time.Time
+}
+
+const attractAfter = 10 * time.Second
+
+// idleMsg checks whether the visitor has left the menu alone.
+type idleMsg struct{}
+
+func idleCheck(after time.Duration) tea.Cmd {
+	return tea.Tick(after, func(time.Time) tea.Msg { return idleMsg{} })
 }
 
-func NewMainModel(renderer *lipgloss.Renderer, w, h int) MainModel {
-	return MainModel{
+func NewMainModel(renderer *lipgloss.Renderer, w, h int, c *content.Content, who identity.Identity, conn servers.Connector, sc *scores.Store, seat *game.Seat) MainModel {
+	player := sc.Player(who)
+	m := MainModel{
 		renderer:  renderer,
 		width:     w,
 		height:    h,
 		cursor:    0,
 		current:   viewHome,
-		portfolio: portfolio.New(renderer, w, h),
-		game:      game.New(renderer, w, h),
-		quote:     pirateQuotes[rand.Intn(len(pirateQuotes))],
+		portfolio: portfolio.New(renderer, w, h, c.Projects),
+		servers:   servers.New(renderer, w, h, c.Servers, conn),
+		about:     c.About,
+		quote:     pickQuote(c.Quotes),
+		content:   c,
+		who:       who,
+		scores:    sc,
+		player:    player,
+		seat:      seat,
 	}
+	return m
+}
+
+// session is the visitor as the games see them.
+func (m MainModel) session() game.Session {
+	return game.Session{
+		Renderer: m.renderer,
+		Width:    m.width,
+		Height:   m.height,
+		Player:   m.player,
+		Name:     m.playerName(),
+		Seat:     m.seat,
+		Content:  m.content,
+	}
+}
+
+func pickQuote(quotes []string) string {
+	if len(quotes) == 0 {
+		return ""
+	}
+	return quotes[rand.Intn(len(quotes))]
+}
+
+// WithReplay opens the portal on replay id, for `ssh host replay <id>`.
+func (m MainModel) WithReplay(id string) MainModel {
+	m.current = viewReplays
+	m.replays, m.start = game.NewReplays(m.renderer, m.width, m.height, m.seat).Open(id)
+	return m
 }
 
 func (m MainModel) Init() tea.Cmd {
-	return nil
+	return tea.Batch(m.start, idleCheck(attractAfter))
 }
 
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -95,20 +165,97 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		updated, _ := m.servers.Update(msg)
+		m.servers = updated.(servers.Model)
+		updated, _ = m.portfolio.Update(msg)
+		m.portfolio = updated.(portfolio.Model)
+		updated, _ = m.spectate.Update(msg)
+		m.spectate = updated.(game.Spectator)
+		updated, _ = m.replays.Update(msg)
+		m.replays = updated.(game.Replays)
+		// The arena and the leaderboard are built at the current size on
+		// the way in, so only the one on screen needs the new size.
+		switch m.current {
+		case viewArena:
+			updated, _ = m.arena.Update(msg)
+			m.arena = updated.(game.Arena)
+		case viewLeaderboard:
+			updated, _ = m.board.Update(msg)
+			m.board = updated.(game.Leaderboard)
+		}
+		if m.game != nil {
+			updated, _ = m.game.Update(msg)
+			m.game = updated.(game.Game)
+		}
+		if m.attract {
+			updated, cmd := m.demo.Update(msg)
+			m.demo = updated.(game.Game)
+			return m, cmd
+		}
+		return m, nil
+
+	case *content.Content:
+		// Content was reloaded on disk; swap it in without touching the
+		// visitor's position.
+		m.about = msg.About
+		m.portfolio.SetProjects(msg.Projects)
+		m.servers.SetList(msg.Servers)
+		m.content = msg
+		if !slices.Contains(msg.Quotes, m.quote) {
+			m.quote = pickQuote(msg.Quotes)
+		}
+		if m.game != nil {
+			updated, _ := m.game.Update(msg)
+			m.game = updated.(game.Game)
+		}
+		return m, nil
+
+	case servers.Statuses:
+		m.servers.SetStatuses(msg)
+		return m, nil
+
+	case servers.ConnectedMsg:
+		updated, cmd := m.servers.Update(msg)
+		m.servers = updated.(servers.Model)
+		return m, cmd
+
+	case idleMsg:
+		if idle := time.Since(m.lastKey); idle < attractAfter {
+			return m, idleCheck(attractAfter - idle)
+		}
+		if info, ok := m.demoGame(); ok {
+			m.attract = true
+			m.demo = info.Attract(m.session())
+			return m, m.demo.Init()
+		}
+		return m, idleCheck(attractAfter)
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		m.lastKey = time.Now()
+		// Any key but ctrl+c only ends the demo.
+		if m.attract && msg.String() != "ctrl+c" {
+			m.attract = false
+			return m, idleCheck(attractAfter)
+		}
+		switch key := msg.String(); {
+		case key == "ctrl+c":
 			return m, tea.Quit
-		case "q":
+		case m.typing():
+		case (key == "q" || key == "esc") && m.current == viewSpectate && m.spectate.Watching():
+			m.spectate = m.spectate.Back()
+			return m, nil
+		case (key == "q" || key == "esc") && m.current == viewReplays && m.replays.Watching():
+			m.replays = m.replays.Back()
+			return m, nil
+		case key == "q":
 			if m.current == viewHome {
 				return m, tea.Quit
 			}
-			m.current = viewHome
+			m.goHome()
 			return m, nil
-		case "esc":
+		case key == "esc":
 			if m.current != viewHome {
-				m.current = viewHome
+				m.goHome()
 				return m, nil
 			}
 		}
@@ -118,15 +265,42 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.portfolio = updated.(portfolio.Model)
 			return m, cmd
 		}
+		if m.current == viewServers {
+			updated, cmd := m.servers.Update(msg)
+			m.servers = updated.(servers.Model)
+			return m, cmd
+		}
+		if m.current == viewGames {
+			return m.updateGames(msg)
+		}
 		if m.current == viewGame {
 			updated, cmd := m.game.Update(msg)
-			m.game = updated.(game.Model)
-			if m.game.Quit {
-				m.current = viewHome
-				m.game = game.New(m.renderer, m.width, m.height)
+			m.game = updated.(game.Game)
+			if m.game.Done() {
+				m.goHome()
 			}
 			return m, cmd
 		}
+		if m.current == viewArena {
+			updated, cmd := m.arena.Update(msg)
+			m.arena = updated.(game.Arena)
+			return m, cmd
+		}
+		if m.current == viewSpectate {
+			updated, cmd := m.spectate.Update(msg)
+			m.spectate = updated.(game.Spectator)
+			return m, cmd
+		}
+		if m.current == viewReplays {
+			updated, cmd := m.replays.Update(msg)
+			m.replays = updated.(game.Replays)
+			return m, cmd
+		}
+		if m.current == viewLeaderboard {
+			updated, cmd := m.board.Update(msg)
+			m.board = updated.(game.Leaderboard)
+			return m, cmd
+		}
 
 		switch msg.String() {
 		case "up", "k":
@@ -140,35 +314,169 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter", " ":
 			selected := menuItems[m.cursor]
 			m.current = selected.view
-			if selected.view == viewGame {
-				m.game = game.New(m.renderer, m.width, m.height)
-				return m, m.game.Init()
+			switch selected.view {
+			case viewArena:
+				m.arena = game.NewArena(m.renderer, m.width, m.height, m.seat, m.playerName())
+				return m, m.arena.Init()
+			case viewSpectate:
+				m.spectate = game.NewSpectator(m.renderer, m.width, m.height, m.seat)
+				return m, m.spectate.Init()
+			case viewReplays:
+				m.replays = game.NewReplays(m.renderer, m.width, m.height, m.seat)
+			case viewLeaderboard:
+				var boards []game.Board
+				for _, info := range game.Games() {
+					if info.Boards != nil {
+						boards = append(boards, info.Boards(m.scores, time.Now())...)
+					}
+				}
+				m.board = game.NewLeaderboard(m.renderer, m.width, m.height, m.scores, m.player, "", boards).Show(m.played)
 			}
 		}
+		return m, nil
+	}
+
+	// The portfolio's toast times out even if the visitor has moved on.
+	updated, cmd := m.portfolio.Update(msg)
+	m.portfolio = updated.(portfolio.Model)
+
+	if m.attract {
+		updated, demoCmd := m.demo.Update(msg)
+		m.demo = updated.(game.Game)
+		return m, tea.Batch(cmd, demoCmd)
 	}
 
 	if m.current == viewGame {
-		updated, cmd := m.game.Update(msg)
-		m.game = updated.(game.Model)
-		return m, cmd
+		updated, gameCmd := m.game.Update(msg)
+		m.game = updated.(game.Game)
+		return m, tea.Batch(cmd, gameCmd)
+	}
+	if m.current == viewArena {
+		updated, arenaCmd := m.arena.Update(msg)
+		m.arena = updated.(game.Arena)
+		return m, tea.Batch(cmd, arenaCmd)
+	}
+	if m.current == viewSpectate {
+		updated, spectateCmd := m.spectate.Update(msg)
+		m.spectate = updated.(game.Spectator)
+		return m, tea.Batch(cmd, spectateCmd)
+	}
+	if m.current == viewReplays {
+		updated, replayCmd := m.replays.Update(msg)
+		m.replays = updated.(game.Replays)
+		return m, tea.Batch(cmd, replayCmd)
 	}
 
-	return m, nil
+	return m, cmd
 }
 
 func (m MainModel) View() string {
+	if m.attract {
+		return m.demo.View()
+	}
 	switch m.current {
 	case viewAbout:
 		return m.aboutView()
 	case viewPortfolio:
 		return m.portfolio.View()
+	case viewServers:
+		return m.servers.View()
+	case viewGames:
+		return m.gamesView()
 	case viewGame:
 		return m.game.View()
+	case viewArena:
+		return m.arena.View()
+	case viewSpectate:
+		return m.spectate.View()
+	case viewReplays:
+		return m.replays.View()
+	case viewLeaderboard:
+		return m.board.View()
 	default:
 		return m.homeView()
 	}
 }
 
+// goHome returns to the menu, letting the section being left tell the
+// other sessions: the visitor's snake leaves the arena and spectators stop
+// watching others. A game, which has already said goodbye, goes back to
+// the Games menu.
+func (m *MainModel) goHome() {
+	switch m.current {
+	case viewGame:
+		m.played = m.game.ScoreKey()
+		m.game = nil
+		m.current = viewGames
+		return
+	case viewArena:
+		m.arena.Leave()
+	case viewSpectate:
+		m.spectate.Close()
+	}
+	m.current = viewHome
+}
+
+// demoGame is the game whose demo to show while the menu rests on it: the
+// one picked on the Games menu, or the first with a demo when it's Games
+// on the home menu.
+func (m MainModel) demoGame() (game.Info, bool) {
+	switch {
+	case m.current == viewHome && menuItems[m.cursor].view == viewGames:
+		for _, info := range game.Games() {
+			if info.Attract != nil {
+				return info, true
+			}
+		}
+	case m.current == viewGames:
+		info := game.Games()[m.gameCursor]
+		return info, info.Attract != nil
+	}
+	return game.Info{}, false
+}
+
+func (m MainModel) updateGames(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.gameCursor > 0 {
+			m.gameCursor--
+		}
+	case "down", "j":
+		if m.gameCursor < len(game.Games())-1 {
+			m.gameCursor++
+		}
+	case "enter", " ":
+		m.current = viewGame
+		m.game = game.Games()[m.gameCursor].New(m.session())
+		return m, m.game.Init()
+	}
+	return m, nil
+}
+
+// playerName is what other players and spectators see: the leaderboard
+// nickname if there is one.
+func (m MainModel) playerName() string {
+	if nick := m.player.Nickname(); nick != "" {
+		return nick
+	}
+	if m.who.Anonymous() {
+		return fmt.Sprintf("stranger %d", m.seat.ID())
+	}
+	return m.who.Name()
+}
+
+// typing reports whether the current section takes q and esc itself: it
+// has a text field focused, or it's a game, which says when it's done.
+func (m MainModel) typing() bool {
+	switch m.current {
+	case viewGame:
+		return true
+	case viewLeaderboard:
+		return m.board.Typing()
+	}
+	return false
+}
+
 func (m MainModel) homeView() string {
 	r := m.renderer
 
@@ -196,6 +504,12 @@ func (m MainModel) homeView() string {
 	sb.WriteString("\n")
 	sb.WriteString(taglineStyle.Render("  " + m.quote))
 	sb.WriteString("\n\n")
+	if m.who.Anonymous() {
+		sb.WriteString(descStyle.Render("  👤 Hello, stranger! Connect with an SSH key and the portal will remember you."))
+	} else {
+		sb.WriteString(normalStyle.Render("  👤 Welcome, "+m.who.Name()) + "  " + descStyle.Render(m.who.KeyType+" "+m.who.Short()))
+	}
+	sb.WriteString("\n\n")
 
 	sb.WriteString(r.NewStyle().Foreground(yellow).Bold(true).Render("  Navigate"))
 	sb.WriteString("\n")
@@ -216,6 +530,39 @@ func (m MainModel) homeView() string {
 	return sb.String()
 }
 
+func (m MainModel) gamesView() string {
+	r := m.renderer
+
+	pink   := lipgloss.Color("#FF79C6")
+	fg     := lipgloss.Color("#F8F8F2")
+	subtle := lipgloss.Color("#6272A4")
+
+	selStyle    := r.NewStyle().Foreground(pink).Bold(true)
+	normalStyle := r.NewStyle().Foreground(fg)
+	descStyle   := r.NewStyle().Foreground(subtle).Italic(true)
+	footStyle   := r.NewStyle().Foreground(subtle).Italic(true)
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(r.NewStyle().Foreground(pink).Bold(true).Render("  🎮 Games"))
+	sb.WriteString("\n\n")
+	for i, info := range game.Games() {
+		line := fmt.Sprintf("%s  %s", info.Icon, info.Name)
+		if i == m.gameCursor {
+			sb.WriteString(selStyle.Render("  ▸ " + line))
+			sb.WriteString("  " + descStyle.Render(info.Desc))
+		} else {
+			sb.WriteString(normalStyle.Render("    " + line))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(footStyle.Render("  ↑↓ / j k to move  •  enter to play  •  esc / q to go back"))
+
+	return sb.String()
+}
+
 func (m MainModel) aboutView() string {
 	r := m.renderer
 
@@ -242,36 +589,38 @@ func (m MainModel) aboutView() string {
 	sb.WriteString(titleStyle.Render("  👋 About & Welcome"))
 	sb.WriteString("\n\n")
 
-	whoami := fmt.Sprintf(
-		"%s\n\n%s\n%s\n%s\n\n%s\n%s\n\n%s\n%s %s %s %s %s %s %s",
-		hlStyle.Render("  Hey, I'm Koossaayy! 👾"),
-		labelStyle.Render("  What I do:"),
-		valStyle.Render("  Developer, homelab nerd, terminal maximalist. I build things,"),
-		valStyle.Render("  break them, learn why, and repeat. Because why not 🤷‍♂️"),
-		labelStyle.Render("  Currently into:"),
-		valStyle.Render("  Laravel, Serious DevSecOps, Self-hosting everything, Go, CLI aesthetics."),
-		labelStyle.Render("  Stack:"),
-		r.NewStyle().Background(lipgloss.Color("#F55673")).Foreground(fg).Bold(true).Padding(0, 1).Render("Laravel & PHP (I mean of course)"),
-		r.NewStyle().Background(lipgloss.Color("#6272FF")).Foreground(fg).Bold(true).Padding(0, 1).Render("Finetuning Models"),
-		r.NewStyle().Background(lipgloss.Color("#F1FA8C")).Foreground(lipgloss.Color("#282A36")).Bold(true).Padding(0, 1).Render("React / JS"),
-		r.NewStyle().Background(lipgloss.Color("#00ADD8")).Foreground(fg).Bold(true).Padding(0, 1).Render("Go"),
-		r.NewStyle().Background(lipgloss.Color("#2496ED")).Foreground(fg).Bold(true).Padding(0, 1).Render("Docker (I hate it though)"),
-		r.NewStyle().Background(lipgloss.Color("#FFA500")).Foreground(lipgloss.Color("#282A36")).Bold(true).Padding(0, 1).Render("Linux but mostly Windows (MacOS soon)"),
-		r.NewStyle().Background(lipgloss.Color("#7B42BC")).Foreground(fg).Bold(true).Padding(0, 1).Render("Coolify FTW"),
-	)
-	sb.WriteString(boxStyle.Render(whoami))
+	a := m.about
+
+	var who strings.Builder
+	who.WriteString(hlStyle.Render("  " + a.Greeting))
+	for _, sec := range a.Sections {
+		who.WriteString("\n\n")
+		who.WriteString(labelStyle.Render("  " + sec.Title + ":"))
+		for _, line := range strings.Split(sec.Body, "\n") {
+			who.WriteString("\n")
+			who.WriteString(valStyle.Render("  " + line))
+		}
+	}
+	if len(a.Stack) > 0 {
+		var badges []string
+		for _, b := range a.Stack {
+			badges = append(badges, r.NewStyle().Background(lipgloss.Color(b.BG)).Foreground(lipgloss.Color(b.FG)).Bold(true).Padding(0, 1).Render(b.Label))
+		}
+		who.WriteString("\n\n")
+		who.WriteString(labelStyle.Render("  Stack:"))
+		who.WriteString("\n" + strings.Join(badges, " "))
+	}
+	sb.WriteString(boxStyle.Render(who.String()))
 	sb.WriteString("\n\n")
 
-	links := fmt.Sprintf(
-		"%s\n%s  %s\n%s  %s\n%s  %s\n%s  %s\n%s  %s",
-		labelStyle.Render("  Find me:"),
-		r.NewStyle().Foreground(cyan).Render("  🌐 Web   "), valStyle.Render("https://koossaayy.tn"),
-		r.NewStyle().Foreground(cyan).Render("  🐙 GitHub"), valStyle.Render("https://github.com/koossaayy"),
-		r.NewStyle().Foreground(cyan).Render("  🐦 Twitter"), valStyle.Render("https://x.com/koossaayy"),
-		r.NewStyle().Foreground(cyan).Render("  🔗 LinkedIn"), valStyle.Render("https://www.linkedin.com/in/koossaayy/"),
-		r.NewStyle().Foreground(cyan).Render("  📡 SSH   "), valStyle.Render("ssh ssh.koossaayy.tn -p 69  ← yes, port 69. yes, on purpose. Thank you"),
-	)
-	sb.WriteString(boxStyle.Render(links))
+	var links strings.Builder
+	links.WriteString(labelStyle.Render("  Find me:"))
+	for _, l := range a.Links {
+		links.WriteString("\n")
+		links.WriteString(r.NewStyle().Foreground(cyan).Render(fmt.Sprintf("  %s %-6s", l.Icon, l.Label)))
+		links.WriteString("  " + valStyle.Render(l.URL))
+	}
+	sb.WriteString(boxStyle.Render(links.String()))
 
 	sb.WriteString("\n\n")
 	sb.WriteString(footStyle.Render("  esc / q to go back"))
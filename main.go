@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"errors"
-	"net"
+	"flag"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,24 +17,133 @@ import (
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/charmbracelet/wish/logging"
+	"github.com/muesli/termenv"
+	gossh "golang.org/x/crypto/ssh"
 
+	"github.com/koossaayy/ssh-portal/internal/blocks"
+	"github.com/koossaayy/ssh-portal/internal/commands"
+	"github.com/koossaayy/ssh-portal/internal/config"
+	"github.com/koossaayy/ssh-portal/internal/content"
+	"github.com/koossaayy/ssh-portal/internal/game"
+	"github.com/koossaayy/ssh-portal/internal/hostkey"
+	"github.com/koossaayy/ssh-portal/internal/identity"
+	"github.com/koossaayy/ssh-portal/internal/jump"
+	"github.com/koossaayy/ssh-portal/internal/scores"
+	"github.com/koossaayy/ssh-portal/internal/servers"
+	"github.com/koossaayy/ssh-portal/internal/typing"
 	"github.com/koossaayy/ssh-portal/internal/ui"
 )
 
-const (
-	host = "0.0.0.0"
-	port = "2222"
-)
-
 func main() {
-	s, err := wish.NewServer(
-		wish.WithAddress(net.JoinHostPort(host, port)),
-		wish.WithHostKeyPath("/app/app/data/.ssh/id_ed25519"),
-		wish.WithMiddleware(
-			bubbletea.Middleware(teaHandler),
-			logging.Middleware(),
-		),
-	)
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Error("Could not load configuration", "error", err)
+		os.Exit(1)
+	}
+	if cfg.File != "" {
+		log.Info("Loaded configuration", "file", cfg.File)
+	}
+
+	keys, err := hostkey.EnsureAll(cfg.KeyPath(), cfg.HostKeyTypes, cfg.HostKeys)
+	if err != nil {
+		log.Error("Could not load host keys", "error", err)
+		os.Exit(1)
+	}
+
+	if len(cfg.Command) > 0 {
+		switch cfg.Command[0] {
+		case "known-hosts":
+			_, port := cfg.PublicAddr()
+			for _, line := range hostkey.KnownHosts(keys, cfg.KnownHostsName(), port) {
+				fmt.Println(line)
+			}
+			return
+		default:
+			log.Error("Unknown command", "command", cfg.Command[0], "commands", "known-hosts")
+			os.Exit(2)
+		}
+	}
+
+	for _, k := range keys {
+		msg := "🔑 Using host key"
+		if k.Generated {
+			msg = "🔑 Generated host key"
+		}
+		log.Info(msg, "type", k.Type(), "path", k.Path, "fingerprint", k.Fingerprint())
+		if k.Cert != nil {
+			log.Info("📜 Serving host certificate", "path", k.Path+hostkey.CertSuffix, "principals", k.Cert.ValidPrincipals, "ca", gossh.FingerprintSHA256(k.Cert.SignatureKey))
+		}
+	}
+
+	store, err := content.NewStore(cfg.ContentDir)
+	if err != nil {
+		log.Error("Could not load content", "dir", cfg.ContentDir, "error", err)
+		os.Exit(1)
+	}
+	c := store.Current()
+	log.Info("📚 Loaded content", "dir", cfg.ContentDir, "projects", len(c.Projects), "servers", len(c.Servers), "quotes", len(c.Quotes), "levels", len(c.Levels))
+
+	sc, err := scores.Open(cfg.ScoresPath())
+	if err != nil {
+		log.Error("Could not open the score store", "error", err)
+		os.Exit(1)
+	}
+	defer sc.Close()
+
+	bg, stopBg := context.WithCancel(context.Background())
+	defer stopBg()
+	if cfg.WatchContent {
+		go func() {
+			if err := store.Watch(bg); err != nil {
+				log.Error("Not watching content for changes", "error", err)
+			}
+		}()
+	}
+
+	var prober *servers.Prober
+	if cfg.ProbeInterval > 0 {
+		prober = servers.NewProber(func() []servers.Server { return store.Current().Servers }, cfg.ProbeInterval, cfg.ProbeTimeout)
+		go prober.Run(bg)
+	}
+
+	hub := game.NewHub(sc, cfg.SSHCommand())
+	go hub.Run(bg)
+
+	// The Games menu lists these, in this order.
+	game.Register(game.Snake, game.Daily, blocks.Info, typing.Info)
+
+	var jumper *jump.Jumper
+	if cfg.JumpAllowlist != "" {
+		jumper, err = newJumper(cfg)
+		if err != nil {
+			log.Error("Could not set up jumping", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	opts := []ssh.Option{
+		wish.WithAddress(cfg.Addr()),
+		hostkey.ServerOption(keys),
+		wish.WithIdleTimeout(cfg.IdleTimeout),
+		wish.WithMaxTimeout(cfg.MaxTimeout),
+	}
+	middleware := []wish.Middleware{
+		bubbletea.MiddlewareWithProgramHandler(programHandler(store, prober, jumper, sc, hub), termenv.Ascii),
+	}
+	// The jump allowlist needs to see visitors' keys even when identifying
+	// them is otherwise turned off.
+	if cfg.IdentifyVisitors || jumper != nil {
+		opts = append(opts, identity.ServerOptions()...)
+	}
+	if jumper != nil {
+		middleware = append(middleware, jumper.Middleware())
+	}
+	middleware = append(middleware, commands.Middleware(store, sc), identity.Middleware(), logging.Middleware())
+
+	s, err := wish.NewServer(append(opts, wish.WithMiddleware(middleware...))...)
 	if err != nil {
 		log.Error("Could not start server", "error", err)
 		os.Exit(1)
@@ -41,7 +152,7 @@ func main() {
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
-	log.Info("🌟 SSH Portal starting", "host", host, "port", port)
+	log.Info("🌟 SSH Portal starting", "host", cfg.Host, "port", cfg.Port)
 
 	go func() {
 		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
@@ -59,17 +170,86 @@ func main() {
 	}
 }
 
-func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
-	pty, _, _ := s.Pty()
-	w := pty.Window.Width
-	h := pty.Window.Height
-	if w == 0 {
-		w = 220
+// newJumper loads the allowlist and the portal's client key for jumping to
+// Server Directory entries.
+func newJumper(cfg config.Config) (*jump.Jumper, error) {
+	allow, err := jump.LoadAllowlist(cfg.JumpAllowlist)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hostkey.Ensure(cfg.JumpKey(), hostkey.Ed25519)
+	if err != nil {
+		return nil, err
 	}
-	if h == 0 {
-		h = 50
+	msg := "🪂 Using jump key"
+	if key.Generated {
+		msg = "🪂 Generated jump key, authorize it on your servers"
+	}
+	log.Info(msg, "path", key.Path, "public", strings.TrimSpace(string(gossh.MarshalAuthorizedKey(key.Signer.PublicKey()))))
+	log.Info("🪂 Jumping enabled", "allowlist", cfg.JumpAllowlist, "keys", allow.Len(), "known_hosts", cfg.JumpKnownHostsFile(), "forward_agent", cfg.JumpForwardAgent)
+	return jump.New(jump.Options{
+		Allowlist:    allow,
+		Key:          key.Signer,
+		KnownHosts:   cfg.JumpKnownHostsFile(),
+		ForwardAgent: cfg.JumpForwardAgent,
+	}), nil
+}
+
+// programHandler builds the TUI for a session and keeps it fed with
+// content reloads and server statuses until the session ends.
+func programHandler(store *content.Store, prober *servers.Prober, jumper *jump.Jumper, sc *scores.Store, hub *game.Hub) bubbletea.ProgramHandler {
+	return func(s ssh.Session) *tea.Program {
+		// Without a terminal the middleware never runs the program, so
+		// nothing would take what the forwarders below send it. Turn the
+		// session away before subscribing to anything.
+		pty, _, ok := s.Pty()
+		if !ok {
+			wish.Fatalln(s, "no active terminal, skipping")
+			return nil
+		}
+		w := pty.Window.Width
+		h := pty.Window.Height
+		if w == 0 {
+			w = 220
+		}
+		if h == 0 {
+			h = 50
+		}
+		renderer := bubbletea.MakeRenderer(s)
+		var conn servers.Connector
+		if jumper != nil {
+			conn = jumper.Connector(s)
+		}
+		m := ui.NewMainModel(renderer, w, h, store.Current(), identity.Of(s), conn, sc, hub.Seat(s.Context()))
+		// The replay command falls through to here when there's a terminal
+		// to play it on.
+		if args := s.Command(); len(args) == 2 && args[0] == "replay" {
+			m = m.WithReplay(args[1])
+		}
+		p := tea.NewProgram(m, append(bubbletea.MakeOptions(s), tea.WithAltScreen())...)
+
+		updates, unsubscribe := store.Subscribe()
+		go forward(s.Context(), p, updates, unsubscribe)
+		if prober != nil {
+			statuses, unsubscribe := prober.Subscribe()
+			go func() {
+				p.Send(prober.Snapshot())
+				forward(s.Context(), p, statuses, unsubscribe)
+			}()
+		}
+		return p
+	}
+}
+
+// forward relays values from a subscription into p until ctx ends.
+func forward[T any](ctx context.Context, p *tea.Program, ch <-chan T, unsubscribe func()) {
+	defer unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case v := <-ch:
+			p.Send(v)
+		}
 	}
-	renderer := bubbletea.MakeRenderer(s)
-	m := ui.NewMainModel(renderer, w, h)
-	return m, []tea.ProgramOption{tea.WithAltScreen()}
 }
\ No newline at end of file